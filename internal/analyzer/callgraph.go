@@ -0,0 +1,693 @@
+package analyzer
+
+import (
+	"bufio"
+	"context"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+// LEARNING MOMENT: Reachability Instead of Inactivity
+//
+// A utility function nobody edits for a year is not dead if main() calls it.
+// For Go we can prove that statically:
+//
+// 1. Parse + type-check every package in the clone (go/parser, go/types).
+//    Imports outside the repo are stubbed - no module downloads needed.
+// 2. Every package-level declaration becomes a node:
+//    "dir|(*Cache).Get", "dir|NewCache", "dir|Cache"
+// 3. Every identifier that resolves to another node adds an edge.
+// 4. Walk from the roots: main, init, Test*, exported API, package-level
+//    var initializers. A reachable type also reaches its methods that
+//    implement an interface (the call goes through the interface), and a
+//    value passed to code outside the repo keeps its exported methods alive.
+// 5. Whatever the walk never touched is unreachable.
+//
+// Nodes are keyed by name rather than by types.Object, so foo_linux.go and
+// foo_windows.go declaring the same function share one verdict.
+
+const (
+	// rootNode is the synthetic caller of every entrypoint
+	rootNode = ""
+
+	// escapeSuffix marks "a value of this type left the repo": any of its
+	// exported methods may be called through an interface we can't see
+	escapeSuffix = "#escape"
+)
+
+// Methods that satisfy interfaces we can't see (stdlib, third-party).
+// A reachable type keeps these alive.
+var wellKnownMethods = map[string]bool{
+	"String": true, "GoString": true, "Format": true, "Error": true,
+	"Unwrap": true, "Is": true, "As": true,
+	"ServeHTTP": true, "RoundTrip": true,
+	"MarshalJSON": true, "UnmarshalJSON": true,
+	"MarshalText": true, "UnmarshalText": true,
+	"MarshalBinary": true, "UnmarshalBinary": true,
+	"MarshalYAML": true, "UnmarshalYAML": true,
+	"Scan": true, "Value": true,
+	"Len": true, "Less": true, "Swap": true, "Push": true, "Pop": true,
+	"Read": true, "Write": true, "Close": true, "Seek": true,
+	"ReadFrom": true, "WriteTo": true,
+}
+
+var (
+	moduleLine     = regexp.MustCompile(`^module\s+"?([^"\s]+)"?`)
+	majorVersionEl = regexp.MustCompile(`^v\d+$`)
+)
+
+// GoCallGraph holds reachability verdicts for Go declarations in a repository
+type GoCallGraph struct {
+	// file path -> unit name (as GoParser names it) -> reachable
+	verdicts map[string]map[string]bool
+}
+
+// goPackage is one type-checkable unit: a directory's package, or its
+// external _test package
+type goPackage struct {
+	dir        string
+	importPath string
+	name       string
+	files      []*ast.File
+	paths      []string // repo-relative path per file
+	public     bool     // exported identifiers are API (not main, not internal/)
+}
+
+type callGraphBuilder struct {
+	fset     *token.FileSet
+	packages map[string]*goPackage // import path -> package
+	checked  map[string]*types.Package
+	info     *types.Info
+
+	objKeys  map[types.Object]string
+	declared map[string][]declSite // node key -> where it's declared
+	edges    map[string]map[string]bool
+	typeObjs []*types.TypeName
+}
+
+type declSite struct {
+	path string
+	name string
+}
+
+// BuildGoCallGraph type-checks the Go code under repoPath and computes which
+// declarations are reachable. Returns nil when the repo has no Go code.
+func BuildGoCallGraph(ctx context.Context, repoPath string) (*GoCallGraph, error) {
+	b := &callGraphBuilder{
+		fset:     token.NewFileSet(),
+		packages: make(map[string]*goPackage),
+		checked:  make(map[string]*types.Package),
+		info: &types.Info{
+			Defs:  make(map[*ast.Ident]types.Object),
+			Uses:  make(map[*ast.Ident]types.Object),
+			Types: make(map[ast.Expr]types.TypeAndValue),
+		},
+		objKeys:  make(map[types.Object]string),
+		declared: make(map[string][]declSite),
+		edges:    make(map[string]map[string]bool),
+	}
+
+	if err := b.loadPackages(repoPath); err != nil {
+		return nil, err
+	}
+	if len(b.packages) == 0 {
+		return nil, nil
+	}
+
+	for importPath := range b.packages {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		b.check(importPath)
+	}
+
+	for _, pkg := range b.packages {
+		b.collectDecls(pkg)
+	}
+	for _, pkg := range b.packages {
+		b.collectEdges(pkg)
+	}
+	b.linkInterfaceMethods()
+
+	reachable := b.walk()
+
+	graph := &GoCallGraph{verdicts: make(map[string]map[string]bool)}
+	for key, sites := range b.declared {
+		for _, site := range sites {
+			if graph.verdicts[site.path] == nil {
+				graph.verdicts[site.path] = make(map[string]bool)
+			}
+			graph.verdicts[site.path][site.name] = reachable[key]
+		}
+	}
+
+	return graph, nil
+}
+
+// Reachable reports the verdict for a GoParser unit. Closures ("F.handler",
+// "F.func1") inherit the verdict of the declaration that contains them.
+// ok is false when the unit wasn't part of the analysis.
+func (cg *GoCallGraph) Reachable(filePath, unitName string) (reachable bool, ok bool) {
+	if cg == nil {
+		return false, false
+	}
+
+	units := cg.verdicts[filePath]
+	for name := unitName; name != ""; {
+		if verdict, exists := units[name]; exists {
+			return verdict, true
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	return false, false
+}
+
+// applyReachability copies call-graph verdicts onto a file's function stats
+func applyReachability(analysis *models.FileAnalysis, graph *GoCallGraph) {
+	if graph == nil || analysis.Language != string(parser.LangGo) {
+		return
+	}
+
+	for _, fn := range analysis.Functions {
+		if reachable, ok := graph.Reachable(analysis.Path, fn.Name); ok {
+			fn.Reachable = &reachable
+		}
+	}
+}
+
+// loadPackages parses every .go file and groups them into packages
+func (b *callGraphBuilder) loadPackages(repoPath string) error {
+	modules := make(map[string]string) // module root dir -> module path
+	dirFiles := make(map[string][]string)
+
+	err := fs.WalkDir(os.DirFS(repoPath), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if p != "." && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "vendor" || name == "testdata" || name == "node_modules") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.Name() == "go.mod":
+			if modulePath := readModulePath(filepath.Join(repoPath, p)); modulePath != "" {
+				modules[path.Dir(p)] = modulePath
+			}
+		case strings.HasSuffix(p, ".go"):
+			dirFiles[path.Dir(p)] = append(dirFiles[path.Dir(p)], p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for dir, paths := range dirFiles {
+		importPath := resolveImportPath(dir, modules)
+
+		files := make(map[string]*ast.File)
+		nameVotes := make(map[string]int)
+		for _, p := range paths {
+			file, err := goparser.ParseFile(b.fset, filepath.Join(repoPath, p), nil, goparser.SkipObjectResolution)
+			if err != nil || file == nil {
+				continue
+			}
+			files[p] = file
+			if !strings.HasSuffix(p, "_test.go") {
+				nameVotes[file.Name.Name]++
+			}
+		}
+
+		// The package is whatever most non-test files declare; stray files
+		// (e.g. "//go:build ignore" generators) are left out
+		pkgName := ""
+		for name, votes := range nameVotes {
+			if votes > nameVotes[pkgName] || (votes == nameVotes[pkgName] && name < pkgName) {
+				pkgName = name
+			}
+		}
+
+		for _, p := range paths {
+			file, ok := files[p]
+			if !ok {
+				continue
+			}
+
+			pkgPath, name := importPath, file.Name.Name
+			switch {
+			case strings.HasSuffix(p, "_test.go") && strings.HasSuffix(name, "_test"):
+				// External test package: not importable by anyone
+				pkgPath = importPath + "_test"
+			case pkgName != "" && name != pkgName:
+				continue
+			}
+
+			pkg, exists := b.packages[pkgPath]
+			if !exists {
+				pkg = &goPackage{
+					dir:        dir,
+					importPath: pkgPath,
+					name:       name,
+					public:     name != "main" && !strings.HasSuffix(name, "_test") && !isInternalPath(dir),
+				}
+				b.packages[pkgPath] = pkg
+			}
+
+			pkg.files = append(pkg.files, file)
+			pkg.paths = append(pkg.paths, p)
+		}
+	}
+
+	return nil
+}
+
+// check type-checks a repo package (and, through Import, its repo deps)
+func (b *callGraphBuilder) check(importPath string) *types.Package {
+	if pkg, done := b.checked[importPath]; done {
+		return pkg
+	}
+
+	pkg := b.packages[importPath]
+
+	// Mark before checking so import cycles terminate
+	placeholder := types.NewPackage(importPath, pkg.name)
+	b.checked[importPath] = placeholder
+
+	conf := types.Config{
+		Importer:    importerFunc(b.importPackage),
+		FakeImportC: true,
+		Error:       func(error) {}, // Missing third-party deps are expected - keep going
+	}
+
+	checked, _ := conf.Check(importPath, b.fset, pkg.files, b.info)
+	if checked != nil {
+		b.checked[importPath] = checked
+		return checked
+	}
+
+	return placeholder
+}
+
+// importPackage resolves repo packages from source and stubs everything else
+func (b *callGraphBuilder) importPackage(importPath string) (*types.Package, error) {
+	if _, inRepo := b.packages[importPath]; inRepo {
+		return b.check(importPath), nil
+	}
+
+	stub := types.NewPackage(importPath, guessPackageName(importPath))
+	stub.MarkComplete()
+	return stub, nil
+}
+
+// collectDecls creates a node for every package-level declaration
+func (b *callGraphBuilder) collectDecls(pkg *goPackage) {
+	for i, file := range pkg.files {
+		filePath := pkg.paths[i]
+
+		for _, d := range file.Decls {
+			switch decl := d.(type) {
+			case *ast.FuncDecl:
+				name := parser.GoFuncName(decl)
+				key := b.declare(pkg, filePath, name, b.info.Defs[decl.Name])
+
+				if b.isRoot(pkg, filePath, decl) {
+					b.addEdge(rootNode, key)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						obj := b.info.Defs[s.Name]
+						key := b.declare(pkg, filePath, s.Name.Name, obj)
+
+						if typeName, ok := obj.(*types.TypeName); ok {
+							b.typeObjs = append(b.typeObjs, typeName)
+						}
+						if pkg.public && s.Name.IsExported() {
+							b.addEdge(rootNode, key)
+						}
+					case *ast.ValueSpec:
+						for _, ident := range s.Names {
+							if ident.Name == "_" {
+								continue
+							}
+							key := b.declare(pkg, filePath, ident.Name, b.info.Defs[ident])
+							if pkg.public && ident.IsExported() {
+								b.addEdge(rootNode, key)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (b *callGraphBuilder) declare(pkg *goPackage, filePath, name string, obj types.Object) string {
+	key := pkg.importPath + "|" + name
+	if obj != nil {
+		b.objKeys[obj] = key
+	}
+	b.declared[key] = append(b.declared[key], declSite{path: filePath, name: name})
+	return key
+}
+
+// isRoot decides whether a function is an entrypoint on its own
+func (b *callGraphBuilder) isRoot(pkg *goPackage, filePath string, decl *ast.FuncDecl) bool {
+	name := decl.Name.Name
+
+	if decl.Recv == nil {
+		if name == "init" || (name == "main" && pkg.name == "main") {
+			return true
+		}
+
+		if strings.HasSuffix(filePath, "_test.go") {
+			for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+				if strings.HasPrefix(name, prefix) {
+					return true
+				}
+			}
+		}
+
+		return pkg.public && decl.Name.IsExported()
+	}
+
+	// Exported method on an exported type of a public package
+	return pkg.public && decl.Name.IsExported() && len(decl.Recv.List) > 0 &&
+		receiverBase(decl.Recv.List[0].Type).IsExported()
+}
+
+// receiverBase strips pointers and type parameters: *Cache[K, V] -> Cache
+func receiverBase(expr ast.Expr) *ast.Ident {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t
+	case *ast.StarExpr:
+		return receiverBase(t.X)
+	case *ast.ParenExpr:
+		return receiverBase(t.X)
+	case *ast.IndexExpr:
+		return receiverBase(t.X)
+	case *ast.IndexListExpr:
+		return receiverBase(t.X)
+	default:
+		return ast.NewIdent("_")
+	}
+}
+
+// collectEdges links each declaration to everything it references
+func (b *callGraphBuilder) collectEdges(pkg *goPackage) {
+	for _, file := range pkg.files {
+		for _, d := range file.Decls {
+			switch decl := d.(type) {
+			case *ast.FuncDecl:
+				from := b.objKeys[b.info.Defs[decl.Name]]
+				b.collectRefs(decl, from)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						b.collectRefs(s.Type, b.objKeys[b.info.Defs[s.Name]])
+					case *ast.ValueSpec:
+						b.collectValueRefs(s)
+					}
+				}
+			}
+		}
+	}
+}
+
+// collectValueRefs handles package-level vars: initializers run at startup
+// (root edges), but function literals only run when the var is used
+func (b *callGraphBuilder) collectValueRefs(spec *ast.ValueSpec) {
+	for i, value := range spec.Values {
+		owner := rootNode
+		if len(spec.Values) == len(spec.Names) {
+			owner = b.objKeys[b.info.Defs[spec.Names[i]]]
+		}
+
+		ast.Inspect(value, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				b.collectRefs(lit, owner)
+				return false
+			}
+			b.visit(rootNode, n)
+			return true
+		})
+	}
+
+	if spec.Type != nil && len(spec.Names) > 0 {
+		b.collectRefs(spec.Type, b.objKeys[b.info.Defs[spec.Names[0]]])
+	}
+}
+
+func (b *callGraphBuilder) collectRefs(node ast.Node, from string) {
+	// An unresolved declaration has no key and falls back to rootNode,
+	// so nothing it calls gets a false "unreachable"
+	ast.Inspect(node, func(n ast.Node) bool {
+		b.visit(from, n)
+		return true
+	})
+}
+
+func (b *callGraphBuilder) visit(from string, n ast.Node) {
+	switch node := n.(type) {
+	case *ast.Ident:
+		b.addRef(from, node)
+	case *ast.CallExpr:
+		// Values handed to code outside the repo may be used through
+		// interfaces we can't see: fmt.Println(v), encoder.Encode(&patch{})
+		if b.isExternal(node.Fun) {
+			for _, arg := range node.Args {
+				b.addEscape(from, arg)
+			}
+		}
+	case *ast.CompositeLit:
+		// types.Config{Importer: importerFunc(f)}
+		if node.Type != nil && b.isExternal(node) {
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				b.addEscape(from, elt)
+			}
+		}
+	}
+}
+
+// isExternal: the expression's type is unknown because it comes from a stub
+func (b *callGraphBuilder) isExternal(expr ast.Expr) bool {
+	tv, ok := b.info.Types[expr]
+	if ok && (tv.IsBuiltin() || tv.IsType()) {
+		return false
+	}
+	return !ok || tv.Type == nil || tv.Type == types.Typ[types.Invalid]
+}
+
+func (b *callGraphBuilder) addEscape(from string, expr ast.Expr) {
+	tv, ok := b.info.Types[expr]
+	if !ok || tv.Type == nil {
+		return
+	}
+
+	t := tv.Type
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		if key, ok := b.objKeys[named.Origin().Obj()]; ok {
+			b.addEdge(from, key+escapeSuffix)
+		}
+	}
+}
+
+func (b *callGraphBuilder) addRef(from string, ident *ast.Ident) {
+	obj := b.info.Uses[ident]
+	switch o := obj.(type) {
+	case *types.Func:
+		obj = o.Origin()
+	case *types.Var:
+		obj = o.Origin()
+	}
+
+	if to, ok := b.objKeys[obj]; ok && to != from {
+		b.addEdge(from, to)
+	}
+}
+
+func (b *callGraphBuilder) addEdge(from, to string) {
+	if b.edges[from] == nil {
+		b.edges[from] = make(map[string]bool)
+	}
+	b.edges[from][to] = true
+}
+
+// linkInterfaceMethods adds type -> method edges for methods that are called
+// through an interface rather than directly
+func (b *callGraphBuilder) linkInterfaceMethods() {
+	interfaces := []*types.Interface{}
+	for _, typeName := range b.typeObjs {
+		if iface, ok := typeName.Type().Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+			interfaces = append(interfaces, iface)
+		}
+	}
+
+	for _, typeName := range b.typeObjs {
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || types.IsInterface(named) {
+			continue
+		}
+		from := b.objKeys[typeName]
+		generic := named.TypeParams().Len() > 0
+
+		implemented := make(map[string]bool)
+		for _, iface := range interfaces {
+			if generic || types.Implements(types.NewPointer(named), iface) {
+				for i := 0; i < iface.NumMethods(); i++ {
+					implemented[iface.Method(i).Name()] = true
+				}
+			}
+		}
+
+		b.addEdge(from+escapeSuffix, from)
+
+		methods := types.NewMethodSet(types.NewPointer(named))
+		for i := 0; i < methods.Len(); i++ {
+			fn, ok := methods.At(i).Obj().(*types.Func)
+			if !ok {
+				continue
+			}
+			to, ok := b.objKeys[fn.Origin()]
+			if !ok {
+				continue
+			}
+
+			if implemented[fn.Name()] || wellKnownMethods[fn.Name()] {
+				b.addEdge(from, to)
+			} else if fn.Exported() {
+				b.addEdge(from+escapeSuffix, to)
+			}
+		}
+	}
+}
+
+// walk is a plain BFS from the synthetic root
+func (b *callGraphBuilder) walk() map[string]bool {
+	reachable := map[string]bool{rootNode: true}
+	queue := []string{rootNode}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for next := range b.edges[current] {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return reachable
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+func readModulePath(goModPath string) string {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if matches := moduleLine.FindStringSubmatch(strings.TrimSpace(scanner.Text())); matches != nil {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// resolveImportPath maps a repo directory to its import path using the
+// closest enclosing go.mod. Without one, the directory itself is the key.
+func resolveImportPath(dir string, modules map[string]string) string {
+	bestRoot, bestPath := "", ""
+	found := false
+
+	for root, modulePath := range modules {
+		if root != "." && dir != root && !strings.HasPrefix(dir, root+"/") {
+			continue
+		}
+		if !found || len(root) > len(bestRoot) {
+			bestRoot, bestPath, found = root, modulePath, true
+		}
+	}
+
+	if !found {
+		return dir
+	}
+
+	rel := dir
+	if bestRoot != "." {
+		rel = strings.TrimPrefix(strings.TrimPrefix(dir, bestRoot), "/")
+	}
+	if rel == "." || rel == "" {
+		return bestPath
+	}
+	return bestPath + "/" + rel
+}
+
+func isInternalPath(dir string) bool {
+	for _, segment := range strings.Split(dir, "/") {
+		if segment == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// guessPackageName: "gopkg.in/yaml.v3" -> "yaml", "github.com/x/go-git/v5" -> "git"
+func guessPackageName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+
+	if len(parts) > 1 && majorVersionEl.MatchString(name) {
+		name = parts[len(parts)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.ReplaceAll(name, "-", "_")
+}
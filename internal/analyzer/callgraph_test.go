@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+const callGraphMain = `package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+type shape interface{ area() float64 }
+
+type square struct{ side float64 }
+
+func (s square) area() float64   { return s.side * s.side }
+func (s square) perimeter() float64 { return 4 * s.side }
+
+type counter struct{ n int }
+
+func (c *counter) inc()   { c.n++ }
+func (c *counter) reset() { c.n = 0 }
+
+type byLen []string
+
+func (b byLen) Len() int           { return len(b) }
+func (b byLen) Less(i, j int) bool { return len(b[i]) < len(b[j]) }
+func (b byLen) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+type label struct{ text string }
+
+func (l label) String() string { return l.text }
+
+func total(shapes []shape) float64 {
+	sum := 0.0
+	for _, s := range shapes {
+		sum += s.area()
+	}
+	return sum
+}
+
+func handler(w http.ResponseWriter, r *http.Request) { fmt.Fprintln(w, "ok") }
+
+func fromClosure() {}
+
+func neverCalled() {}
+
+func main() {
+	c := &counter{}
+	inc := c.inc
+	inc()
+
+	fmt.Println(total([]shape{square{side: 2}}))
+
+	http.HandleFunc("/", handler)
+
+	run := func() { fromClosure() }
+	run()
+
+	words := byLen{"bb", "a"}
+	sort.Sort(words)
+
+	fmt.Println(label{text: "x"})
+}
+`
+
+func TestGoCallGraphReachability(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.22\n",
+		"main.go": callGraphMain,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := BuildGoCallGraph(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		unit string
+		want bool
+		why  string
+	}{
+		{"main", true, "entrypoint"},
+		{"(*counter).inc", true, "method value"},
+		{"(*counter).reset", false, "method never referenced"},
+		{"(square).area", true, "interface dispatch"},
+		{"(square).perimeter", false, "not in the interface, never called"},
+		{"total", true, "direct call"},
+		{"handler", true, "passed to a stubbed stdlib function"},
+		{"fromClosure", true, "called from a closure in main"},
+		{"main.run", true, "closure inherits its declaration's verdict"},
+		{"(byLen).Len", true, "value passed to stubbed sort.Sort"},
+		{"(byLen).Swap", true, "value passed to stubbed sort.Sort"},
+		{"(label).String", true, "value passed to stubbed fmt.Println"},
+		{"neverCalled", false, "nothing calls it"},
+	}
+
+	for _, tt := range tests {
+		got, ok := graph.Reachable("main.go", tt.unit)
+		if !ok {
+			t.Errorf("%s: no verdict", tt.unit)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: reachable = %v, want %v (%s)", tt.unit, got, tt.want, tt.why)
+		}
+	}
+}
+
+func TestUnreachableFunctionNeedsInactivity(t *testing.T) {
+	hc := NewHeatCalculator()
+	rules, _ := NewRuleEngine(nil)
+	policy := rules.Policy("main.go", "go", functionNode)
+	now := time.Now()
+
+	reachable, unreachable := true, false
+	tests := []struct {
+		name      string
+		reachable *bool
+		edited    time.Time
+		changes   int
+		want      bool
+	}{
+		{"unreachable, edited yesterday", &unreachable, now.AddDate(0, 0, -1), 1, false},
+		{"unreachable, edited yesterday, busy", &unreachable, now.AddDate(0, 0, -1), 10, false},
+		{"unreachable and inactive", &unreachable, now.AddDate(-1, 0, 0), 10, true},
+		{"reachable and inactive", &reachable, now.AddDate(-1, 0, 0), 0, false},
+		{"no call graph, inactive, few changes", nil, now.AddDate(-1, 0, 0), 1, true},
+	}
+
+	for _, tt := range tests {
+		stats := &models.FunctionStats{
+			Name:         "f",
+			Reachable:    tt.reachable,
+			LastModified: tt.edited,
+			TotalChanges: tt.changes,
+		}
+		if got := hc.isLikelyDeadFunctionCode(stats, now, policy); got != tt.want {
+			t.Errorf("%s: dead = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: Reading Intent From Commit Messages
+//
+// A commit message says why code changed. Checked in this order, first hit wins:
+//
+// 1. Configured patterns (commits.patterns in .firesight.yaml or the request)
+// 2. Conventional Commits: "fix(auth)!: ..." -> fix, "feat: ..." -> feature,
+//    refactor/perf -> refactor, chore/docs/style/test/build/ci/revert -> chore
+// 3. Issue keys: "BUG-1234" -> fix (commits.issueKeys adds more, any kind)
+// 4. Keywords: "fixes", "hotfix", "regression" -> fix; "clean up" -> refactor; ...
+//
+// Anything else is "other". Fix commits piling up in one place are the
+// strongest hint there is that the code there is hard to get right, so they
+// get a heat score of their own: defect heat.
+
+// Conventional Commits types, anything not listed is left to the other checks
+var conventionalKinds = map[string]models.CommitKind{
+	"fix":      models.CommitFix,
+	"bugfix":   models.CommitFix,
+	"hotfix":   models.CommitFix,
+	"feat":     models.CommitFeature,
+	"feature":  models.CommitFeature,
+	"refactor": models.CommitRefactor,
+	"perf":     models.CommitRefactor,
+	"chore":    models.CommitChore,
+	"docs":     models.CommitChore,
+	"style":    models.CommitChore,
+	"test":     models.CommitChore,
+	"build":    models.CommitChore,
+	"ci":       models.CommitChore,
+	"revert":   models.CommitChore,
+}
+
+var (
+	conventionalPrefix = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?!?:`)
+
+	// Built-in keywords, in the order they are tried
+	keywordKinds = []struct {
+		kind    models.CommitKind
+		pattern *regexp.Regexp
+	}{
+		{models.CommitFix, regexp.MustCompile(`(?i)\b(?:fix(?:e[sd])?|bug(?:fix)?s?|hotfix|patch(?:ed)?|regression|crash(?:es|ed)?|broken)\b`)},
+		{models.CommitRefactor, regexp.MustCompile(`(?i)\b(?:refactor(?:s|ed|ing)?|clean(?:ed)?[ -]?up|restructur(?:e|ed|ing)|simplif(?:y|ies|ied)|renam(?:e|es|ed))\b`)},
+		{models.CommitChore, regexp.MustCompile(`(?i)\b(?:chore|bump(?:s|ed)?|upgrade[sd]?|dependenc(?:y|ies)|deps|release|docs?|readme|lint|format(?:ting)?|typos?)\b`)},
+		{models.CommitFeature, regexp.MustCompile(`(?i)\b(?:feat(?:ure)?s?|add(?:s|ed)?|implement(?:s|ed)?|introduc(?:e|es|ed)|support(?:s)?)\b`)},
+	}
+)
+
+// Issue keys that mean a fix unless configured otherwise
+var defaultIssueKeys = map[models.CommitKind][]string{
+	models.CommitFix: {"BUG", "DEFECT", "HOTFIX", "INCIDENT"},
+}
+
+// CommitClassifier sorts commits into fix / feature / refactor / chore / other
+type CommitClassifier struct {
+	patterns  []kindPattern // configured, in kind order
+	issueKeys []kindPattern
+}
+
+type kindPattern struct {
+	kind    models.CommitKind
+	pattern *regexp.Regexp
+}
+
+// NewCommitClassifier compiles the commits section of a rules config
+// (nil for the built-in behavior)
+func NewCommitClassifier(cfg *models.RulesConfig) (*CommitClassifier, error) {
+	cc := &CommitClassifier{}
+
+	var rules models.CommitRules
+	if cfg != nil {
+		rules = cfg.Commits
+	}
+
+	for _, kind := range models.CommitKinds {
+		for _, pattern := range rules.Patterns[kind] {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("commits: invalid %s pattern %q: %w", kind, pattern, err)
+			}
+			cc.patterns = append(cc.patterns, kindPattern{kind: kind, pattern: re})
+		}
+	}
+	for kind := range rules.Patterns {
+		if !isCommitKind(kind) {
+			return nil, fmt.Errorf("commits: unknown kind %q", kind)
+		}
+	}
+
+	for _, kind := range models.CommitKinds {
+		keys := append(append([]string{}, rules.IssueKeys[kind]...), defaultIssueKeys[kind]...)
+		if len(keys) == 0 {
+			continue
+		}
+		for i, key := range keys {
+			keys[i] = regexp.QuoteMeta(strings.TrimSuffix(key, "-"))
+		}
+		cc.issueKeys = append(cc.issueKeys, kindPattern{
+			kind:    kind,
+			pattern: regexp.MustCompile(`\b(?:` + strings.Join(keys, "|") + `)-\d+\b`),
+		})
+	}
+	for kind := range rules.IssueKeys {
+		if !isCommitKind(kind) {
+			return nil, fmt.Errorf("commits: unknown kind %q", kind)
+		}
+	}
+
+	return cc, nil
+}
+
+func isCommitKind(kind models.CommitKind) bool {
+	for _, known := range models.CommitKinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify reads a commit message
+func (cc *CommitClassifier) Classify(message string) models.CommitKind {
+	for _, p := range cc.patterns {
+		if p.pattern.MatchString(message) {
+			return p.kind
+		}
+	}
+
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+
+	if matches := conventionalPrefix.FindStringSubmatch(subject); matches != nil {
+		if kind, ok := conventionalKinds[strings.ToLower(matches[1])]; ok {
+			return kind
+		}
+	}
+
+	for _, p := range cc.issueKeys {
+		if p.pattern.MatchString(message) {
+			return p.kind
+		}
+	}
+
+	for _, k := range keywordKinds {
+		if k.pattern.MatchString(subject) {
+			return k.kind
+		}
+	}
+
+	return models.CommitOther
+}
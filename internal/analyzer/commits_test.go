@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func TestCommitClassify(t *testing.T) {
+	cc, err := NewCommitClassifier(&models.RulesConfig{Commits: models.CommitRules{
+		Patterns: map[models.CommitKind][]string{
+			models.CommitRefactor: {`\[cleanup\]`},
+			models.CommitChore:    {`^Merge `},
+		},
+		IssueKeys: map[models.CommitKind][]string{
+			models.CommitFeature: {"FEAT-"},
+			models.CommitChore:   {"OPS"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		message string
+		want    models.CommitKind
+	}{
+		// 1. Configured patterns beat everything
+		{"feat: split the parser [cleanup]", models.CommitRefactor},
+		{"Merge branch 'fix-login'", models.CommitChore},
+		{"Merge BUG-12 hotfix", models.CommitChore},
+
+		// 2. Conventional Commits beat issue keys and keywords
+		{"feat: fix the BUG-12 flow", models.CommitFeature},
+		{"fix(auth)!: drop the legacy tokens", models.CommitFix},
+		{"Refactor: add a cache", models.CommitRefactor},
+		{"docs(readme): fix typo", models.CommitChore},
+		{"wip: fix the crash", models.CommitFix}, // unknown type, keyword decides
+
+		// 3. Issue keys, anywhere in the message, beat keywords
+		{"Update login BUG-42", models.CommitFix},
+		{"Update login\n\nCloses INCIDENT-7", models.CommitFix},
+		{"FEAT-7 fixes the crash", models.CommitFeature},
+		{"OPS-3 add a runbook", models.CommitChore},
+		{"BUG42 fixed", models.CommitFix}, // not a key, the keyword decides
+		{"DEBUG-3 add logging", models.CommitFeature},
+
+		// 4. Keywords, in the subject only, fix first
+		{"Fix and refactor the importer", models.CommitFix},
+		{"Refactor and add tests", models.CommitRefactor},
+		{"Clean up the parser", models.CommitRefactor},
+		{"Bump deps", models.CommitChore},
+		{"Add CSV export", models.CommitFeature},
+		{"Update login\n\nfixes the crash", models.CommitOther},
+
+		{"prefix handling", models.CommitOther},
+		{"", models.CommitOther},
+	}
+
+	for _, tt := range tests {
+		if got := cc.Classify(tt.message); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNewCommitClassifierErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules models.CommitRules
+		want  string
+	}{
+		{"invalid pattern", models.CommitRules{Patterns: map[models.CommitKind][]string{models.CommitFix: {"("}}},
+			`invalid fix pattern "("`},
+		{"unknown pattern kind", models.CommitRules{Patterns: map[models.CommitKind][]string{"security": {"CVE"}}},
+			`unknown kind "security"`},
+		{"unknown issue key kind", models.CommitRules{IssueKeys: map[models.CommitKind][]string{models.CommitOther: {"MISC"}}},
+			`unknown kind "other"`},
+	}
+
+	for _, tt := range tests {
+		_, err := NewCommitClassifier(&models.RulesConfig{Commits: tt.rules})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
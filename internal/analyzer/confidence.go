@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"math"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: From Votes to Confidence
+//
+// IsDeadCode is a yes/no vote, which can't rank a cleanup backlog. Instead,
+// every signal says "how dead does this look" on a 0-1 scale:
+//
+//   age        1 - 0.5^(days / inactiveDays)    0.5 right at the threshold
+//   changes    0.5^(changes / minChanges)       1.0 when never changed
+//   authors    1 / authors                      1.0 for a single author
+//   references 1 / (1 + refs), 0/1 for Go       1.0 when nothing refers to it
+//   coverage   1 - percent / 100                1.0 when no test runs it
+//   entrypoint 0 entrypoint, 0.5 exported API   only when a rule opts them in
+//   imports    1 orphan, 0 reachable             files in the import graph only
+//
+// confidence = Σ weight·score / Σ weight over the signals we actually have.
+// Each signal's contribution (weight·score / Σ weight) is reported, and
+// the contributions add up to the confidence.
+//
+// Coverage is the one signal whose weight moves: fresh untested code is
+// normal, cold untested code is what nobody would miss. Its weight grows
+// with the age score, from 1 (just edited) to 4 (long abandoned).
+
+// Relative weight of each signal; static evidence outweighs git history
+var confidenceWeights = map[models.DeadCodeSignalName]float64{
+	models.SignalAge:        1,
+	models.SignalChanges:    1,
+	models.SignalAuthors:    0.5,
+	models.SignalReferences: 3,
+	models.SignalCoverage:   1, // scaled up with age, see above
+	models.SignalEntrypoint: 2,
+	models.SignalImports:    2,
+}
+
+// confidenceInput collects whatever is known about a node. Unknown
+// signals (nil) are left out instead of counted as zero.
+type confidenceInput struct {
+	daysSinceEdit int
+	changes       int
+	authors       *int
+	reachable     *bool
+	references    *int
+	orphan        *bool
+	coverage      *float64
+	runtimeShare  *float64
+	role          models.FunctionRole
+	suppressed    bool
+}
+
+// deadCodeConfidence scores a node 0-1 and explains the score
+func (hc *HeatCalculator) deadCodeConfidence(in confidenceInput, policy *DeadCodePolicy) (float64, []models.DeadCodeSignal) {
+	if policy.Never || in.suppressed {
+		return 0, nil
+	}
+
+	// Profile samples are proof of life, nothing else matters
+	if hasSamples(in.runtimeShare) {
+		return 0, []models.DeadCodeSignal{{
+			Signal: models.SignalRuntime,
+			Value:  *in.runtimeShare,
+			Weight: 1,
+		}}
+	}
+
+	// Called from outside the repo's view - excluded, or a strong "alive" vote when opted in
+	if policy.excludes(in.role) {
+		return 0, []models.DeadCodeSignal{{
+			Signal: models.SignalEntrypoint,
+			Value:  1,
+			Weight: 1,
+		}}
+	}
+
+	signals := []models.DeadCodeSignal{}
+	add := func(name models.DeadCodeSignalName, value, score float64) {
+		signals = append(signals, models.DeadCodeSignal{
+			Signal: name,
+			Value:  value,
+			Score:  score,
+			Weight: confidenceWeights[name],
+		})
+	}
+
+	inactiveDays := math.Max(float64(policy.InactiveDays), 1)
+	ageScore := 1 - math.Pow(0.5, float64(in.daysSinceEdit)/inactiveDays)
+	add(models.SignalAge, float64(in.daysSinceEdit), ageScore)
+
+	minChanges := math.Max(float64(policy.MinChanges), 1)
+	add(models.SignalChanges, float64(in.changes), math.Pow(0.5, float64(in.changes)/minChanges))
+
+	if in.authors != nil {
+		add(models.SignalAuthors, float64(*in.authors), 1/math.Max(float64(*in.authors), 1))
+	}
+
+	switch {
+	case in.reachable != nil:
+		// Go call graph is a proof, not a count
+		score, value := 0.0, 1.0
+		if !*in.reachable {
+			score, value = 1, 0
+		}
+		add(models.SignalReferences, value, score)
+	case in.references != nil:
+		add(models.SignalReferences, float64(*in.references), 1/(1+float64(*in.references)))
+	}
+
+	if in.orphan != nil {
+		score, value := 0.0, 1.0
+		if *in.orphan {
+			score, value = 1, 0
+		}
+		add(models.SignalImports, value, score)
+	}
+
+	switch in.role {
+	case models.RoleEntrypoint:
+		add(models.SignalEntrypoint, 1, 0)
+	case models.RoleExported:
+		add(models.SignalEntrypoint, 1, 0.5) // maybe used by another repo
+	}
+
+	if in.coverage != nil {
+		add(models.SignalCoverage, *in.coverage, 1-*in.coverage/100)
+		signals[len(signals)-1].Weight *= 1 + 3*ageScore
+	}
+
+	return combineSignals(signals)
+}
+
+func hasSamples(runtimeShare *float64) bool {
+	return runtimeShare != nil && *runtimeShare > 0
+}
+
+// combineSignals fills in contributions and returns the weighted mean
+func combineSignals(signals []models.DeadCodeSignal) (float64, []models.DeadCodeSignal) {
+	totalWeight := 0.0
+	for _, signal := range signals {
+		totalWeight += signal.Weight
+	}
+	if totalWeight == 0 {
+		return 0, signals
+	}
+
+	confidence := 0.0
+	for i := range signals {
+		signals[i].Contribution = signals[i].Weight * signals[i].Score / totalWeight
+		confidence += signals[i].Contribution
+	}
+
+	return confidence, signals
+}
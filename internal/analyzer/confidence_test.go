@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func TestDeadCodeConfidence(t *testing.T) {
+	hc := NewHeatCalculator()
+	rules, _ := NewRuleEngine(nil)
+
+	// Functions: inactiveDays 180, minChanges 2
+	policy := rules.Policy("main.go", "go", functionNode)
+	optedIn := rules.Policy("main.go", "go", functionNode)
+	optedIn.IncludeEntrypoints, optedIn.IncludeExported = true, true
+	never := rules.Policy("main.go", "go", functionNode)
+	never.Never = true
+
+	yes, no := true, false
+	two, three := 2, 3
+	zero, full, half := 0.0, 100.0, 0.5
+
+	tests := []struct {
+		name    string
+		in      confidenceInput
+		policy  *DeadCodePolicy
+		want    float64
+		signals []models.DeadCodeSignalName
+	}{
+		// age 0.5 at the threshold, changes 1 when never changed: (0.5 + 1) / 2
+		{"at the threshold, never changed", confidenceInput{daysSinceEdit: 180}, policy, 0.75,
+			[]models.DeadCodeSignalName{models.SignalAge, models.SignalChanges}},
+		// age 0, changes 0.5 at minChanges
+		{"fresh", confidenceInput{changes: 2}, policy, 0.25, nil},
+		// authors weigh 0.5: (0.5 + 1 + 0.5·0.5) / 2.5
+		{"two authors", confidenceInput{daysSinceEdit: 180, authors: &two}, policy, 0.7, nil},
+		// the call graph weighs 3: (0 + 0.5 + 3) / 5
+		{"unreachable", confidenceInput{changes: 2, reachable: &no}, policy, 0.7, nil},
+		{"reachable", confidenceInput{changes: 2, reachable: &yes}, policy, 0.1, nil},
+		// references score 1 / (1 + refs): (0 + 0.5 + 3·0.25) / 5
+		{"three references", confidenceInput{changes: 2, references: &three}, policy, 0.25, nil},
+		// the call graph beats the symbol index
+		{"reachable with references", confidenceInput{changes: 2, reachable: &yes, references: &three}, policy, 0.1, nil},
+		// imports weigh 2: (0 + 0.5 + 2) / 4
+		{"orphan", confidenceInput{changes: 2, orphan: &yes}, policy, 0.625, nil},
+		// coverage weighs 1 when fresh: (0 + 0.5 + 1) / 3
+		{"uncovered, fresh", confidenceInput{changes: 2, coverage: &zero}, policy, 0.5, nil},
+		// and 1 + 3·0.5 at the threshold: (0.5 + 0.5 + 2.5) / 4.5
+		{"uncovered, inactive", confidenceInput{daysSinceEdit: 180, changes: 2, coverage: &zero}, policy, 3.5 / 4.5, nil},
+		{"covered, inactive", confidenceInput{daysSinceEdit: 180, changes: 2, coverage: &full}, policy, 1.0 / 4.5, nil},
+		// opted-in roles: exported scores 0.5, entrypoints 0, weight 2
+		{"exported, opted in", confidenceInput{changes: 2, role: models.RoleExported}, optedIn, 0.375, nil},
+		{"entrypoint, opted in", confidenceInput{changes: 2, role: models.RoleEntrypoint}, optedIn, 0.125, nil},
+		{"zero runtime share is no proof", confidenceInput{daysSinceEdit: 180, runtimeShare: &zero}, policy, 0.75, nil},
+
+		// Forced to 0
+		{"exported", confidenceInput{daysSinceEdit: 1000, role: models.RoleExported}, policy, 0,
+			[]models.DeadCodeSignalName{models.SignalEntrypoint}},
+		{"entrypoint", confidenceInput{daysSinceEdit: 1000, role: models.RoleEntrypoint}, policy, 0,
+			[]models.DeadCodeSignalName{models.SignalEntrypoint}},
+		{"profile samples", confidenceInput{daysSinceEdit: 1000, reachable: &no, runtimeShare: &half}, optedIn, 0,
+			[]models.DeadCodeSignalName{models.SignalRuntime}},
+		{"suppressed", confidenceInput{daysSinceEdit: 1000, suppressed: true}, policy, 0, []models.DeadCodeSignalName{}},
+		{"never rule", confidenceInput{daysSinceEdit: 1000}, never, 0, []models.DeadCodeSignalName{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, signals := hc.deadCodeConfidence(tt.in, tt.policy)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("confidence = %v, want %v", got, tt.want)
+			}
+
+			if tt.signals != nil {
+				names := []models.DeadCodeSignalName{}
+				for _, s := range signals {
+					names = append(names, s.Signal)
+				}
+				if len(names) != len(tt.signals) {
+					t.Fatalf("signals = %v, want %v", names, tt.signals)
+				}
+				for i := range names {
+					if names[i] != tt.signals[i] {
+						t.Errorf("signals = %v, want %v", names, tt.signals)
+					}
+				}
+			}
+
+			// Contributions explain the score
+			if got > 0 {
+				sum := 0.0
+				for _, s := range signals {
+					sum += s.Contribution
+				}
+				if math.Abs(sum-got) > 1e-9 {
+					t.Errorf("contributions add up to %v, want %v", sum, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: Change Coupling
+//
+// Imports show the dependencies someone wrote down. History shows the ones
+// nobody did: files that keep changing in the same commits.
+//
+//   support = commits that changed both files
+//   degree  = support / average revisions of the two · 100
+//
+//   api/user.go 10 revisions, web/user.ts 6, together in 6
+//   -> support 6, degree 6 / 8 · 100 = 75%
+//
+// A commit touching dozens of files (a rename, a formatter run, a dependency
+// bump) couples everything with everything, so those are skipped. Pairs seen
+// together only once are chance and aren't kept.
+
+const (
+	// Commits changing more files than this don't count toward coupling
+	maxCouplingCommitFiles = 50
+
+	// Fewest shared commits for a pair to be reported
+	minCouplingSupport = 2
+)
+
+// coChanges counts commits per file pair, keyed with the smaller path first
+type coChanges map[[2]string]int
+
+// add records one commit's changed files
+func (cc coChanges) add(paths []string) {
+	if len(paths) < 2 || len(paths) > maxCouplingCommitFiles {
+		return
+	}
+
+	sorted := append([]string{}, paths...)
+	sort.Strings(sorted)
+
+	for i := range sorted {
+		if i > 0 && sorted[i] == sorted[i-1] {
+			continue
+		}
+		for j := i + 1; j < len(sorted); j++ {
+			if sorted[j] == sorted[j-1] {
+				continue
+			}
+			cc[[2]string{sorted[i], sorted[j]}]++
+		}
+	}
+}
+
+// couplings scores the pairs whose files both still exist, strongest first
+func (cc coChanges) couplings(fileStats map[string]*models.FileChangeStats) []models.FileCoupling {
+	couplings := []models.FileCoupling{}
+
+	for pair, support := range cc {
+		a, b := fileStats[pair[0]], fileStats[pair[1]]
+		if a == nil || b == nil || support < minCouplingSupport {
+			continue
+		}
+
+		revisions := float64(a.TotalChanges+b.TotalChanges) / 2
+		couplings = append(couplings, models.FileCoupling{
+			FileA:   pair[0],
+			FileB:   pair[1],
+			Support: support,
+			Degree:  min(100, float64(support)/revisions*100),
+		})
+	}
+
+	sortCouplings(couplings)
+	return couplings
+}
+
+// sortCouplings: highest degree first, then most shared commits, then by path
+func sortCouplings(couplings []models.FileCoupling) {
+	sort.Slice(couplings, func(i, j int) bool {
+		a, b := couplings[i], couplings[j]
+		if a.Degree != b.Degree {
+			return a.Degree > b.Degree
+		}
+		if a.Support != b.Support {
+			return a.Support > b.Support
+		}
+		if a.FileA != b.FileA {
+			return a.FileA < b.FileA
+		}
+		return a.FileB < b.FileB
+	})
+}
+
+// CoupledPartners lists the files most often changed with file, strongest
+// first (limit <= 0 for all)
+func CoupledPartners(couplings []models.FileCoupling, file string, limit int) []models.CoupledFile {
+	partners := []models.CoupledFile{}
+
+	// couplings are sorted, so partners come out in order
+	for _, c := range couplings {
+		var partner string
+		switch file {
+		case c.FileA:
+			partner = c.FileB
+		case c.FileB:
+			partner = c.FileA
+		default:
+			continue
+		}
+
+		partners = append(partners, models.CoupledFile{Path: partner, Support: c.Support, Degree: c.Degree})
+		if limit > 0 && len(partners) == limit {
+			break
+		}
+	}
+
+	return partners
+}
+
+// CrossDirectoryCouplings lists the strongest pairs whose files live in
+// different directories (limit <= 0 for all)
+func CrossDirectoryCouplings(couplings []models.FileCoupling, limit int) []models.FileCoupling {
+	cross := []models.FileCoupling{}
+
+	for _, c := range couplings {
+		if path.Dir(c.FileA) == path.Dir(c.FileB) {
+			continue
+		}
+
+		cross = append(cross, c)
+		if limit > 0 && len(cross) == limit {
+			break
+		}
+	}
+
+	return cross
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func couplingStats(revisions map[string]int) map[string]*models.FileChangeStats {
+	stats := map[string]*models.FileChangeStats{}
+	for p, n := range revisions {
+		stats[p] = &models.FileChangeStats{TotalChanges: n}
+	}
+	return stats
+}
+
+func TestCoChangesAdd(t *testing.T) {
+	cc := make(coChanges)
+	cc.add([]string{"b.go", "a.go"})
+	cc.add([]string{"a.go", "b.go", "a.go", "c.go"}) // duplicates count once
+	cc.add([]string{"a.go"})                         // a single file has no pair
+
+	want := coChanges{{"a.go", "b.go"}: 2, {"a.go", "c.go"}: 1, {"b.go", "c.go"}: 1}
+	if len(cc) != len(want) {
+		t.Fatalf("pairs = %v, want %v", cc, want)
+	}
+	for pair, n := range want {
+		if cc[pair] != n {
+			t.Errorf("%v: support %d, want %d", pair, cc[pair], n)
+		}
+	}
+}
+
+func TestCoChangesCommitCap(t *testing.T) {
+	files := func(n int) []string {
+		paths := make([]string, n)
+		for i := range paths {
+			paths[i] = fmt.Sprintf("f%02d.go", i)
+		}
+		return paths
+	}
+
+	cc := make(coChanges)
+	cc.add(files(maxCouplingCommitFiles))
+	if got, want := len(cc), maxCouplingCommitFiles*(maxCouplingCommitFiles-1)/2; got != want {
+		t.Errorf("a %d-file commit: %d pairs, want %d", maxCouplingCommitFiles, got, want)
+	}
+
+	cc = make(coChanges)
+	cc.add(files(maxCouplingCommitFiles + 1))
+	if len(cc) != 0 {
+		t.Errorf("a %d-file commit: %d pairs, want none", maxCouplingCommitFiles+1, len(cc))
+	}
+}
+
+func TestCouplings(t *testing.T) {
+	cc := coChanges{
+		{"api/user.go", "web/user.ts"}:      6, // learning moment example
+		{"api/user.go", "api/user_test.go"}: 4,
+		{"a.go", "b.go"}:                    1,  // below minCouplingSupport
+		{"api/user.go", "deleted.go"}:       5,  // no longer in the tree
+		{"x.go", "y.go"}:                    12, // support above the revisions caps at 100
+	}
+	stats := couplingStats(map[string]int{
+		"api/user.go": 10, "web/user.ts": 6, "api/user_test.go": 6,
+		"a.go": 1, "b.go": 1, "x.go": 4, "y.go": 4,
+	})
+
+	want := []models.FileCoupling{
+		{FileA: "x.go", FileB: "y.go", Support: 12, Degree: 100},
+		{FileA: "api/user.go", FileB: "web/user.ts", Support: 6, Degree: 75},
+		{FileA: "api/user.go", FileB: "api/user_test.go", Support: 4, Degree: 50},
+	}
+
+	got := cc.couplings(stats)
+	if len(got) != len(want) {
+		t.Fatalf("couplings = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].FileA != want[i].FileA || got[i].FileB != want[i].FileB ||
+			got[i].Support != want[i].Support || math.Abs(got[i].Degree-want[i].Degree) > 1e-9 {
+			t.Errorf("coupling %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	partners := CoupledPartners(got, "api/user.go", 0)
+	if len(partners) != 2 || partners[0].Path != "web/user.ts" || partners[1].Path != "api/user_test.go" {
+		t.Errorf("partners = %+v", partners)
+	}
+	if partners := CoupledPartners(got, "api/user.go", 1); len(partners) != 1 {
+		t.Errorf("limit 1: partners = %+v", partners)
+	}
+
+	cross := CrossDirectoryCouplings(got, 0)
+	if len(cross) != 1 || cross[0].FileB != "web/user.ts" {
+		t.Errorf("cross-directory = %+v", cross)
+	}
+}
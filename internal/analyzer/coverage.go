@@ -0,0 +1,326 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: Coverage as Line Sets
+//
+// Every coverage format boils down to "line N of file F ran K times":
+//
+//   Go coverprofile   a.go:12.5,15.2 3 1   -> lines 12..15 ran once
+//   LCOV              SF:a.js / DA:12,1    -> line 12 ran once
+//   Cobertura         <line number="12" hits="1"/> inside <class filename="a.py">
+//
+// We parse all of them into path -> line -> hits, then:
+//   coverage(range) = lines with hits > 0 / instrumented lines in range
+// Lines the tool didn't instrument (comments, blank lines, declarations)
+// don't count either way, and a range with none gets no coverage at all.
+//
+// Report paths rarely match repo paths: Go uses import paths, LCOV often
+// absolute CI paths, Cobertura paths relative to a source root. A report
+// path is matched to the repo path sharing the longest path suffix.
+
+const (
+	CoverageFormatGo        = "go"
+	CoverageFormatLCOV      = "lcov"
+	CoverageFormatCobertura = "cobertura"
+)
+
+var goCoverBlock = regexp.MustCompile(`^(.+):(\d+)\.\d+,(\d+)\.\d+ \d+ (\d+)$`)
+
+// Coverage is the merged content of one or more coverage reports
+type Coverage struct {
+	files map[string]map[int]int // report path -> line -> hits
+}
+
+// ParseCoverageReports parses and merges reports. Returns nil for no reports.
+func ParseCoverageReports(reports []models.CoverageReport) (*Coverage, error) {
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	coverage := &Coverage{files: make(map[string]map[int]int)}
+
+	for i, report := range reports {
+		name := report.Name
+		if name == "" {
+			name = fmt.Sprintf("report %d", i+1)
+		}
+
+		format := strings.ToLower(report.Format)
+		if format == "" {
+			format = detectCoverageFormat(report.Content)
+		}
+
+		var err error
+		switch format {
+		case CoverageFormatGo, "coverprofile":
+			err = coverage.parseGoProfile(report.Content)
+		case CoverageFormatLCOV:
+			err = coverage.parseLCOV(report.Content)
+		case CoverageFormatCobertura:
+			err = coverage.parseCobertura(report.Content)
+		case "":
+			err = fmt.Errorf("could not detect coverage format, set one of go, lcov, cobertura")
+		default:
+			err = fmt.Errorf("unknown coverage format %q", report.Format)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return coverage, nil
+}
+
+func detectCoverageFormat(content string) string {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "mode:"):
+		return CoverageFormatGo
+	case strings.HasPrefix(trimmed, "<") && strings.Contains(trimmed, "<coverage"):
+		return CoverageFormatCobertura
+	case strings.HasPrefix(trimmed, "TN:") || strings.HasPrefix(trimmed, "SF:"):
+		return CoverageFormatLCOV
+	default:
+		return ""
+	}
+}
+
+func (c *Coverage) record(file string, line, hits int) {
+	file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+
+	lines, ok := c.files[file]
+	if !ok {
+		lines = make(map[int]int)
+		c.files[file] = lines
+	}
+	lines[line] += hits
+}
+
+// parseGoProfile: "mode: set" header, then "file:l.c,l.c statements count"
+func (c *Coverage) parseGoProfile(content string) error {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		matches := goCoverBlock.FindStringSubmatch(line)
+		if matches == nil {
+			return fmt.Errorf("line %d: malformed coverprofile block", lineNum)
+		}
+
+		start, _ := strconv.Atoi(matches[2])
+		end, _ := strconv.Atoi(matches[3])
+		hits, _ := strconv.Atoi(matches[4])
+
+		for l := start; l <= end; l++ {
+			c.record(matches[1], l, hits)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseLCOV reads SF/DA records; everything else (FN, BRDA, ...) is ignored
+func (c *Coverage) parseLCOV(content string) error {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	current := ""
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = strings.TrimPrefix(line, "SF:")
+		case line == "end_of_record":
+			current = ""
+		case strings.HasPrefix(line, "DA:"):
+			if current == "" {
+				return fmt.Errorf("line %d: DA record outside of a source file", lineNum)
+			}
+
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return fmt.Errorf("line %d: malformed DA record", lineNum)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return fmt.Errorf("line %d: malformed DA record", lineNum)
+			}
+			hits, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return fmt.Errorf("line %d: malformed DA record", lineNum)
+			}
+
+			c.record(current, number, hits)
+		}
+	}
+
+	return scanner.Err()
+}
+
+type coberturaReport struct {
+	Sources []string `xml:"sources>source"`
+	Classes []struct {
+		Filename string          `xml:"filename,attr"`
+		Lines    []coberturaLine `xml:"lines>line"`
+	} `xml:"packages>package>classes>class"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// parseCobertura reads class lines; paths are relative to one of the sources
+func (c *Coverage) parseCobertura(content string) error {
+	var report coberturaReport
+	if err := xml.Unmarshal([]byte(content), &report); err != nil {
+		return err
+	}
+
+	for _, class := range report.Classes {
+		filename := class.Filename
+		// A single source root can be joined safely; with several the
+		// suffix matching in resolve sorts it out
+		if len(report.Sources) == 1 && !path.IsAbs(filename) {
+			filename = path.Join(strings.ReplaceAll(report.Sources[0], "\\", "/"), filename)
+		}
+
+		for _, line := range class.Lines {
+			c.record(filename, line.Number, line.Hits)
+		}
+	}
+
+	return nil
+}
+
+// resolve maps report paths onto repo paths
+func (c *Coverage) resolve(repoPaths []string) map[string]map[int]int {
+	if c == nil {
+		return nil
+	}
+
+	resolver := newPathResolver(repoPaths)
+
+	resolved := make(map[string]map[int]int)
+	for reportPath, lines := range c.files {
+		repoPath, ok := resolver.resolve(reportPath)
+		if !ok {
+			continue
+		}
+
+		merged, ok := resolved[repoPath]
+		if !ok {
+			merged = make(map[int]int)
+			resolved[repoPath] = merged
+		}
+		for line, hits := range lines {
+			merged[line] += hits
+		}
+	}
+
+	return resolved
+}
+
+// pathResolver matches foreign paths (import paths, absolute CI or build
+// paths) to the repo path sharing the longest path suffix
+type pathResolver struct {
+	byBase map[string][]string
+}
+
+func newPathResolver(repoPaths []string) *pathResolver {
+	byBase := make(map[string][]string)
+	for _, p := range repoPaths {
+		byBase[path.Base(p)] = append(byBase[path.Base(p)], p)
+	}
+	for _, candidates := range byBase {
+		sort.Strings(candidates)
+	}
+
+	return &pathResolver{byBase: byBase}
+}
+
+func (pr *pathResolver) resolve(foreignPath string) (string, bool) {
+	foreignPath = path.Clean(strings.ReplaceAll(foreignPath, "\\", "/"))
+
+	best, bestLen, tied := "", 0, false
+	for _, candidate := range pr.byBase[path.Base(foreignPath)] {
+		switch n := commonSuffixSegments(foreignPath, candidate); {
+		case n > bestLen:
+			best, bestLen, tied = candidate, n, false
+		case n == bestLen:
+			tied = true
+		}
+	}
+
+	// no match, or two repo files match equally well
+	return best, best != "" && !tied
+}
+
+// commonSuffixSegments counts trailing path segments a and b share
+func commonSuffixSegments(a, b string) int {
+	as := strings.Split(strings.Trim(a, "/"), "/")
+	bs := strings.Split(strings.Trim(b, "/"), "/")
+
+	n := 0
+	for n < len(as) && n < len(bs) && as[len(as)-1-n] == bs[len(bs)-1-n] {
+		n++
+	}
+	return n
+}
+
+// coveragePercent: covered / instrumented lines in [start, end], nil if none instrumented
+func coveragePercent(lines map[int]int, start, end int) *float64 {
+	instrumented, covered := 0, 0
+
+	for line, hits := range lines {
+		if line < start || line > end {
+			continue
+		}
+		instrumented++
+		if hits > 0 {
+			covered++
+		}
+	}
+
+	if instrumented == 0 {
+		return nil
+	}
+
+	percent := float64(covered) / float64(instrumented) * 100
+	return &percent
+}
+
+// applyCoverage sets coverage on a file and its functions
+func applyCoverage(analysis *models.FileAnalysis, lines map[int]int) {
+	if len(lines) == 0 {
+		return
+	}
+
+	analysis.Coverage = coveragePercent(lines, 1, math.MaxInt)
+
+	for _, fn := range analysis.Functions {
+		if fn.Cell != 0 {
+			continue // notebook lines are cell-relative, reports don't cover notebooks
+		}
+		fn.Coverage = coveragePercent(lines, fn.LineStart, fn.LineEnd)
+	}
+}
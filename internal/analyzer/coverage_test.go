@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+var coverageRepoPaths = []string{
+	"internal/api/handler.go",
+	"cmd/server/main.go",
+	"tools/gen/main.go",
+	"web/src/app.js",
+	"app/views.py",
+	"lib/views.py",
+}
+
+func TestParseCoverageReports(t *testing.T) {
+	tests := []struct {
+		name   string
+		report models.CoverageReport
+		want   map[string]map[int]int
+	}{
+		{
+			name: "go coverprofile",
+			report: models.CoverageReport{Format: "go", Content: `mode: set
+github.com/x/y/internal/api/handler.go:3.10,5.2 2 1
+github.com/x/y/internal/api/handler.go:7.10,8.2 1 0
+github.com/x/y/cmd/server/main.go:4.13,6.2 1 1
+`},
+			want: map[string]map[int]int{
+				"internal/api/handler.go": {3: 1, 4: 1, 5: 1, 7: 0, 8: 0},
+				"cmd/server/main.go":      {4: 1, 5: 1, 6: 1},
+			},
+		},
+		{
+			name: "lcov with absolute CI paths, detected",
+			report: models.CoverageReport{Content: `TN:
+SF:/home/ci/build/web/src/app.js
+FN:1,start
+FNDA:1,start
+DA:1,4
+DA:2,0
+BRDA:2,0,0,1
+end_of_record
+`},
+			want: map[string]map[int]int{
+				"web/src/app.js": {1: 4, 2: 0},
+			},
+		},
+		{
+			name: "cobertura under a source root, detected",
+			report: models.CoverageReport{Content: `<?xml version="1.0" ?>
+<coverage version="7.4">
+  <sources><source>/ci/project</source></sources>
+  <packages>
+    <package name="app">
+      <classes>
+        <class name="views.py" filename="app/views.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="4" hits="0"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`},
+			want: map[string]map[int]int{
+				"app/views.py": {1: 1, 4: 0},
+			},
+		},
+		{
+			name: "ambiguous suffix is dropped",
+			report: models.CoverageReport{Format: "lcov", Content: `SF:main.go
+DA:1,1
+end_of_record
+SF:views.py
+DA:1,1
+end_of_record
+SF:C:\ci\tools\gen\main.go
+DA:2,1
+end_of_record
+`},
+			want: map[string]map[int]int{
+				"tools/gen/main.go": {2: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coverage, err := ParseCoverageReports([]models.CoverageReport{tt.report})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := coverage.resolve(coverageRepoPaths)
+			if len(got) != len(tt.want) {
+				t.Fatalf("resolved %v, want %v", got, tt.want)
+			}
+			for path, wantLines := range tt.want {
+				gotLines := got[path]
+				if len(gotLines) != len(wantLines) {
+					t.Errorf("%s: lines %v, want %v", path, gotLines, wantLines)
+					continue
+				}
+				for line, hits := range wantLines {
+					if gotLines[line] != hits {
+						t.Errorf("%s:%d: hits %d, want %d", path, line, gotLines[line], hits)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseCoverageReportsMerges(t *testing.T) {
+	coverage, err := ParseCoverageReports([]models.CoverageReport{
+		{Format: "go", Content: "mode: count\nexample.com/app/cmd/server/main.go:4.1,5.2 1 2\n"},
+		{Format: "lcov", Content: "SF:/ci/cmd/server/main.go\nDA:5,3\nDA:6,0\nend_of_record\n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := coverage.resolve(coverageRepoPaths)["cmd/server/main.go"]
+	want := map[int]int{4: 2, 5: 5, 6: 0}
+	for line, hits := range want {
+		if lines[line] != hits {
+			t.Errorf("line %d: hits %d, want %d", line, lines[line], hits)
+		}
+	}
+}
+
+func TestParseCoverageReportsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		report models.CoverageReport
+		want   string
+	}{
+		{"malformed coverprofile", models.CoverageReport{Name: "unit.out", Format: "go", Content: "mode: set\nhandler.go 3 1\n"}, "unit.out: line 2"},
+		{"DA outside a file", models.CoverageReport{Format: "lcov", Content: "DA:1,1\n"}, "outside of a source file"},
+		{"malformed DA", models.CoverageReport{Format: "lcov", Content: "SF:a.js\nDA:x,1\n"}, "malformed DA"},
+		{"bad xml", models.CoverageReport{Format: "cobertura", Content: "<coverage><packages>"}, "report 1"},
+		{"unknown format", models.CoverageReport{Format: "jacoco", Content: "<report/>"}, `unknown coverage format "jacoco"`},
+		{"undetectable", models.CoverageReport{Content: "hello"}, "could not detect"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCoverageReports([]models.CoverageReport{tt.report})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestApplyCoverage(t *testing.T) {
+	// Lines 3-5 and 7-8 instrumented, 3-5 ran
+	lines := map[int]int{3: 1, 4: 1, 5: 1, 7: 0, 8: 0}
+	covered := &models.FunctionStats{Name: "covered", LineStart: 2, LineEnd: 6}
+	half := &models.FunctionStats{Name: "half", LineStart: 5, LineEnd: 8}
+	declaration := &models.FunctionStats{Name: "declaration", LineStart: 10, LineEnd: 12}
+	cell := &models.FunctionStats{Name: "cell[1]", Cell: 1, LineStart: 3, LineEnd: 5}
+	analysis := &models.FileAnalysis{Functions: []*models.FunctionStats{covered, half, declaration, cell}}
+
+	applyCoverage(analysis, lines)
+
+	check := func(name string, got *float64, want float64) {
+		t.Helper()
+		if got == nil || *got < want-1e-9 || *got > want+1e-9 {
+			t.Errorf("%s: coverage = %v, want %v", name, got, want)
+		}
+	}
+	check("file", analysis.Coverage, 60)
+	check("covered", covered.Coverage, 100)
+	check("half", half.Coverage, 100.0/3)
+	if declaration.Coverage != nil || cell.Coverage != nil {
+		t.Errorf("uninstrumented ranges and notebook cells should have no coverage")
+	}
+}
@@ -0,0 +1,416 @@
+package analyzer
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+// LEARNING MOMENT: Who Calls main()?
+//
+// Git inactivity says nothing about code the outside world calls:
+// main and init run on their own, test runners find TestXxx by name,
+// routers call handlers, other repos import exported API. Every unit gets
+// a role on top of the parser output:
+//
+//   entrypoint  main, init, HTTP handlers registered in routes,
+//               functions called from a Python __main__ block
+//   exported    Go: exported names in importable packages (not main, not internal/)
+//               JS/TS: export / module.exports
+//               Python: __all__, or public functions of a package __init__.py
+//   test        anything in a test file
+//   internal    the rest
+//
+// Entrypoints and exported API are left out of dead-code verdicts unless a
+// rule opts them back in (includeEntrypoints / includeExported).
+
+var (
+	goPackageClause = regexp.MustCompile(`(?m)^package\s+(\w+)`)
+
+	// router.HandleFunc("/users", h.ListUsers) / app.get('/users', auth, listUsers)
+	routeRegistration = regexp.MustCompile(`\.(?i:handle|handlefunc|get|post|put|patch|delete|head|options|all|any|route|add_url_rule)\s*\(\s*(?:"/[^"]*"|'/[^']*'|` + "`/[^`]*`" + `)\s*,(.*)`)
+	// Django: path("users/", views.list_users)
+	djangoRoute = regexp.MustCompile(`\b(?:re_)?path\s*\(\s*r?["'][^"']*["']\s*,(.*)`)
+	// @app.route("/"), @router.get("/users"), @bp.post(...)
+	pyRouteDecorator = regexp.MustCompile(`^\s*@[\w.]*\.(?:route|get|post|put|patch|delete|head|options|websocket|api_route)\s*\(`)
+	pyMainGuard      = regexp.MustCompile(`^if\s+__name__\s*==\s*["']__main__["']\s*:`)
+	pyAllEntry       = regexp.MustCompile(`["'](\w+)["']`)
+	pyCall           = regexp.MustCompile(`\b(\w+)\s*\(`)
+
+	jsExportDecl    = regexp.MustCompile(`^\s*export\s+(?:default\s+)?(?:async\s+)?(?:function\s*\*?|const|let|var|class)\s+([\w$]+)`)
+	jsExportDefault = regexp.MustCompile(`^\s*export\s+default\s+([\w$]+)\s*;?\s*$`)
+	jsExportList    = regexp.MustCompile(`^\s*export\s*\{([^}]*)\}`)
+	jsModuleExports = regexp.MustCompile(`^\s*module\.exports\s*=\s*(?:\{([^}]*)\}|([\w$]+))`)
+	jsNamedExport   = regexp.MustCompile(`^\s*(?:module\.)?exports\.([\w$]+)\s*=`)
+)
+
+var (
+	// views.ListView.as_view / h.ListUsers / listUsers
+	selectorChain = regexp.MustCompile(`[A-Za-z_$][\w$]*(?:\s*\.\s*[A-Za-z_$][\w$]*)*`)
+	quotedString  = regexp.MustCompile(`"[^"]*"|'[^']*'|` + "`[^`]*`")
+	keywordArg    = regexp.MustCompile(`^\s*\w+\s*=[^=>]`)
+	inlineHandler = regexp.MustCompile(`^\s*(?:async\s+)?(?:func|function|lambda)\b|=>`)
+)
+
+// Not handler names: wrappers and helpers around the real handler
+var routeArgKeywords = map[string]bool{
+	"HandlerFunc": true, "as_view": true, "include": true,
+}
+
+// routeHandler is a handler name as seen from where it was registered.
+// scope is "dir:<dir>" for a bare name (same package, or a sibling module
+// it was imported from) or "mod:<qualifier>" for views.list_users
+type routeHandler struct {
+	scope string
+	name  string
+}
+
+// EntrypointClassifier assigns roles to parser units
+type EntrypointClassifier struct {
+	repoPath      string
+	routeHandlers map[routeHandler]bool
+}
+
+// BuildEntrypointClassifier scans the repo for route registrations; handlers
+// are often registered far from where they are defined
+func BuildEntrypointClassifier(repoPath string, paths []string, detector *parser.LanguageDetector) *EntrypointClassifier {
+	ec := &EntrypointClassifier{
+		repoPath:      repoPath,
+		routeHandlers: make(map[routeHandler]bool),
+	}
+
+	for _, p := range paths {
+		content, err := os.ReadFile(filepath.Join(repoPath, p))
+		if err != nil {
+			continue
+		}
+
+		lang := detector.Detect(p, content).Language
+		if lang != parser.LangGo && !isIndexedLanguage(lang) {
+			continue
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			args := ""
+			if matches := routeRegistration.FindStringSubmatch(line); matches != nil {
+				args = matches[1]
+			} else if lang == parser.LangPython {
+				if matches := djangoRoute.FindStringSubmatch(line); matches != nil {
+					args = matches[1]
+				}
+			}
+
+			qualifier, name := routeHandlerArg(args)
+			if name == "" {
+				continue
+			}
+			ec.routeHandlers[routeHandler{"dir:" + path.Dir(p), name}] = true
+			if qualifier != "" {
+				ec.routeHandlers[routeHandler{"mod:" + qualifier, name}] = true
+			}
+		}
+	}
+
+	return ec
+}
+
+// routeHandlerArg picks the handler out of a registration's arguments: the
+// last positional one, unwrapped. h.ListUsers -> "h", "ListUsers";
+// http.HandlerFunc(listUsers) -> "", "listUsers"; inline handlers -> ""
+func routeHandlerArg(args string) (qualifier, name string) {
+	args = quotedString.ReplaceAllString(args, `""`)
+
+	// Everything up to the call's closing paren, split on top-level commas
+	end, depth := len(args), 0
+	for i, r := range args {
+		if r == '(' || r == '[' || r == '{' {
+			depth++
+		} else if r == ')' || r == ']' || r == '}' {
+			if depth--; depth < 0 {
+				end = i
+				break
+			}
+		}
+	}
+
+	var positional []string
+	depth, start := 0, 0
+	for i, r := range args[:end] + "," {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				if arg := args[start:i]; !keywordArg.MatchString(arg) {
+					positional = append(positional, arg)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if len(positional) == 0 {
+		return "", ""
+	}
+
+	handler := positional[len(positional)-1]
+	if inlineHandler.MatchString(handler) {
+		return "", ""
+	}
+
+	chains := selectorChain.FindAllString(handler, -1)
+	for i := len(chains) - 1; i >= 0; i-- {
+		parts := strings.FieldsFunc(chains[i], func(r rune) bool { return r == '.' || unicode.IsSpace(r) })
+		for len(parts) > 0 && routeArgKeywords[parts[len(parts)-1]] {
+			parts = parts[:len(parts)-1]
+		}
+		switch len(parts) {
+		case 0:
+			continue
+		case 1:
+			return "", parts[0]
+		default:
+			return parts[len(parts)-2], parts[len(parts)-1]
+		}
+	}
+	return "", ""
+}
+
+// isRouteHandler: registered from the unit's own package or directory, or
+// through a qualifier naming its package or module
+func (ec *EntrypointClassifier) isRouteHandler(filePath, module, name string) bool {
+	return ec.routeHandlers[routeHandler{"dir:" + path.Dir(filePath), name}] ||
+		ec.routeHandlers[routeHandler{"mod:" + module, name}]
+}
+
+// moduleName is how other files qualify a JS or Python file: views.py -> views,
+// users/index.js -> users
+func moduleName(filePath string) string {
+	base := path.Base(filePath)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	if base == "index" || base == "__init__" {
+		return path.Base(path.Dir(filePath))
+	}
+	return base
+}
+
+// classify sets the role of every unit in a file
+func (ec *EntrypointClassifier) classify(analysis *models.FileAnalysis) {
+	if ec == nil || len(analysis.Functions) == 0 {
+		return
+	}
+
+	content, err := os.ReadFile(filepath.Join(ec.repoPath, analysis.Path))
+	if err != nil {
+		return
+	}
+	lines := strings.Split(string(content), "\n")
+
+	var roleOf func(fn *models.FunctionStats) models.FunctionRole
+	switch parser.Language(analysis.Language) {
+	case parser.LangGo:
+		roleOf = ec.goRoles(analysis.Path, string(content))
+	case parser.LangJavaScript, parser.LangTypeScript, parser.LangVue, parser.LangSvelte, parser.LangAstro:
+		roleOf = ec.jsRoles(analysis.Path, lines)
+	case parser.LangPython:
+		roleOf = ec.pythonRoles(analysis.Path, lines)
+	case parser.LangJupyter:
+		return // cells run top to bottom, nobody calls them
+	default:
+		roleOf = func(fn *models.FunctionStats) models.FunctionRole {
+			if fn.Name == "main" {
+				return models.RoleEntrypoint
+			}
+			return models.RoleInternal
+		}
+	}
+
+	for _, fn := range analysis.Functions {
+		fn.Role = roleOf(fn)
+	}
+}
+
+func (ec *EntrypointClassifier) goRoles(filePath, content string) func(*models.FunctionStats) models.FunctionRole {
+	pkgName := ""
+	if matches := goPackageClause.FindStringSubmatch(content); matches != nil {
+		pkgName = matches[1]
+	}
+
+	isTest := strings.HasSuffix(filePath, "_test.go")
+	public := pkgName != "main" && !isTest && !isInternalPath(path.Dir(filePath))
+
+	return func(fn *models.FunctionStats) models.FunctionRole {
+		receiver, name := splitGoUnitName(fn.Name)
+
+		switch {
+		case isTest:
+			return models.RoleTest
+		case fn.Type == string(parser.TypeClosure):
+			return models.RoleInternal // nobody outside calls a closure by name
+		case receiver == "" && (name == "init" || (name == "main" && pkgName == "main")):
+			return models.RoleEntrypoint
+		case ec.isRouteHandler(filePath, pkgName, name):
+			return models.RoleEntrypoint
+		case public && isExportedName(name) && (receiver == "" || isExportedName(receiver)):
+			return models.RoleExported
+		default:
+			return models.RoleInternal
+		}
+	}
+}
+
+// splitGoUnitName: "(*Cache).Get" -> "Cache", "Get"; "Handler" -> "", "Handler"
+func splitGoUnitName(unitName string) (receiver, name string) {
+	if !strings.HasPrefix(unitName, "(") {
+		return "", unitName
+	}
+
+	end := strings.Index(unitName, ").")
+	if end < 0 {
+		return "", unitName
+	}
+
+	return strings.TrimPrefix(unitName[1:end], "*"), unitName[end+2:]
+}
+
+func isExportedName(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+func (ec *EntrypointClassifier) jsRoles(filePath string, lines []string) func(*models.FunctionStats) models.FunctionRole {
+	base := path.Base(filePath)
+	isTest := strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.Contains("/"+filePath, "/__tests__/")
+
+	exported := make(map[string]bool)
+	for _, line := range lines {
+		if matches := jsExportDecl.FindStringSubmatch(line); matches != nil {
+			exported[matches[1]] = true
+		}
+		if matches := jsExportDefault.FindStringSubmatch(line); matches != nil {
+			exported[matches[1]] = true
+		}
+		if matches := jsNamedExport.FindStringSubmatch(line); matches != nil {
+			exported[matches[1]] = true
+		}
+		if matches := jsModuleExports.FindStringSubmatch(line); matches != nil {
+			if matches[2] != "" {
+				exported[matches[2]] = true
+			}
+			for _, name := range exportListNames(matches[1]) {
+				exported[name] = true
+			}
+		}
+		if matches := jsExportList.FindStringSubmatch(line); matches != nil {
+			for _, name := range exportListNames(matches[1]) {
+				exported[name] = true
+			}
+		}
+	}
+
+	return func(fn *models.FunctionStats) models.FunctionRole {
+		switch {
+		case isTest:
+			return models.RoleTest
+		case fn.Name == "main" || ec.isRouteHandler(filePath, moduleName(filePath), fn.Name):
+			return models.RoleEntrypoint
+		case exported[fn.Name]:
+			return models.RoleExported
+		default:
+			return models.RoleInternal
+		}
+	}
+}
+
+// exportListNames: "a, b as c, d: e" -> the local names a, b, d
+func exportListNames(list string) []string {
+	names := []string{}
+	for _, item := range strings.Split(list, ",") {
+		fields := strings.FieldsFunc(item, func(r rune) bool { return r == ':' || unicode.IsSpace(r) })
+		if len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+func (ec *EntrypointClassifier) pythonRoles(filePath string, lines []string) func(*models.FunctionStats) models.FunctionRole {
+	base := path.Base(filePath)
+	isTest := strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py") || base == "conftest.py"
+	isPackageInit := base == "__init__.py"
+
+	exported := make(map[string]bool)
+	mainCalls := make(map[string]bool)
+	inAll, inMain := false, false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// __all__ = ["a", "b"] (possibly over several lines)
+		if pyExportStart.MatchString(line) {
+			inAll = true
+		}
+		if inAll {
+			for _, matches := range pyAllEntry.FindAllStringSubmatch(line, -1) {
+				exported[matches[1]] = true
+			}
+			if strings.ContainsAny(line, ")]") {
+				inAll = false
+			}
+		}
+
+		// if __name__ == "__main__": runs until the next unindented line
+		if pyMainGuard.MatchString(line) {
+			inMain = true
+			continue
+		}
+		if inMain && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			if line[0] != ' ' && line[0] != '\t' {
+				inMain = false
+			} else {
+				for _, matches := range pyCall.FindAllStringSubmatch(stripPythonComment(line), -1) {
+					mainCalls[matches[1]] = true
+				}
+			}
+		}
+	}
+
+	return func(fn *models.FunctionStats) models.FunctionRole {
+		switch {
+		case isTest:
+			return models.RoleTest
+		case mainCalls[fn.Name] || ec.isRouteHandler(filePath, moduleName(filePath), fn.Name) || hasRouteDecorator(lines, fn.LineStart):
+			return models.RoleEntrypoint
+		case exported[fn.Name]:
+			return models.RoleExported
+		case isPackageInit && fn.Type == string(parser.TypeFunction) && !strings.HasPrefix(fn.Name, "_"):
+			return models.RoleExported
+		default:
+			return models.RoleInternal
+		}
+	}
+}
+
+// hasRouteDecorator looks at the decorators stacked above a def
+func hasRouteDecorator(lines []string, defLine int) bool {
+	for i := defLine - 2; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "@") {
+			return false
+		}
+		if pyRouteDecorator.MatchString(lines[i]) {
+			return true
+		}
+	}
+	return false
+}
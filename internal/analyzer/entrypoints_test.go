@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+func TestRouteHandlerArg(t *testing.T) {
+	tests := []struct {
+		args          string
+		wantQualifier string
+		wantName      string
+	}{
+		{` h.ListUsers)`, "h", "ListUsers"},
+		{` h.ListUsers).Methods("GET")`, "h", "ListUsers"},
+		{` http.HandlerFunc(listUsers))`, "", "listUsers"},
+		{` func(w http.ResponseWriter, r *http.Request) {`, "", ""},
+		{` auth, listUsers);`, "", "listUsers"},
+		{` (req, res) => res.send(users));`, "", ""},
+		{` async function(req, res) {`, "", ""},
+		{` views.list_users, name="users"),`, "views", "list_users"},
+		{` views.UserList.as_view()),`, "views", "UserList"},
+		{` 'index', index)`, "", "index"},
+		{` include("users.urls")),`, "", ""},
+	}
+
+	for _, tt := range tests {
+		qualifier, name := routeHandlerArg(tt.args)
+		if qualifier != tt.wantQualifier || name != tt.wantName {
+			t.Errorf("routeHandlerArg(%q) = %q, %q, want %q, %q", tt.args, qualifier, name, tt.wantQualifier, tt.wantName)
+		}
+	}
+}
+
+func TestRouteHandlersScopedToPackage(t *testing.T) {
+	files := map[string]string{
+		"api/routes.go": `package api
+
+func routes(r *mux.Router, h *Handler) {
+	r.HandleFunc("/users", h.ListUsers).Methods("GET")
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	r.HandleFunc("/version", handlers.Version)
+}
+`,
+		"api/handler.go": `package api
+
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {}
+
+func (h *Handler) Request(w http.ResponseWriter, r *http.Request) {}
+`,
+		"handlers/version.go": `package handlers
+
+func Version(w http.ResponseWriter, r *http.Request) {}
+`,
+		"other/users.go": `package other
+
+func ListUsers() {}
+
+func w() {}
+`,
+		"app/urls.py": `urlpatterns = [
+    path("users/", views.list_users, name="users"),
+]
+`,
+		"app/views.py": `def list_users(request):
+    pass
+
+def name(request):
+    pass
+`,
+	}
+
+	repo := t.TempDir()
+	paths := []string{}
+	for p, content := range files {
+		full := filepath.Join(repo, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+
+	ec := BuildEntrypointClassifier(repo, paths, parser.NewLanguageDetector(repo))
+
+	tests := []struct {
+		path, lang, unit string
+		want             models.FunctionRole
+	}{
+		{"api/handler.go", "go", "(*Handler).ListUsers", models.RoleEntrypoint},
+		{"api/handler.go", "go", "(*Handler).Request", models.RoleExported},
+		{"handlers/version.go", "go", "Version", models.RoleEntrypoint},
+		{"other/users.go", "go", "w", models.RoleInternal},
+		{"app/views.py", "python", "list_users", models.RoleEntrypoint},
+		{"app/views.py", "python", "name", models.RoleInternal},
+	}
+
+	for _, tt := range tests {
+		fn := &models.FunctionStats{Name: tt.unit, Type: string(parser.TypeFunction), LineStart: 1}
+		ec.classify(&models.FileAnalysis{Path: tt.path, Language: tt.lang, Functions: []*models.FunctionStats{fn}})
+		if fn.Role != tt.want {
+			t.Errorf("%s %s: role = %q, want %q", tt.path, tt.unit, fn.Role, tt.want)
+		}
+	}
+
+	// Same name, different package: not the registered handler
+	fn := &models.FunctionStats{Name: "ListUsers", Type: string(parser.TypeFunction), LineStart: 1}
+	ec.classify(&models.FileAnalysis{Path: "other/users.go", Language: "go", Functions: []*models.FunctionStats{fn}})
+	if fn.Role == models.RoleEntrypoint {
+		t.Errorf("other/users.go ListUsers is an entrypoint; only api's ListUsers is registered")
+	}
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/richd0tcom/fire-sight/internal/models"
 	"github.com/richd0tcom/fire-sight/internal/parser"
@@ -27,10 +29,15 @@ import (
 
 type FileAnalyzer struct {
 	repoPath string
+	detector *parser.LanguageDetector
+	commits  *CommitClassifier // nil: no fix tracking
 }
 
 func NewFileAnalyzer(repoPath string) *FileAnalyzer {
-	return &FileAnalyzer{repoPath: repoPath}
+	return &FileAnalyzer{
+		repoPath: repoPath,
+		detector: parser.NewLanguageDetector(repoPath),
+	}
 }
 
 // AnalyzeFile reads file content and extracts function-level metrics
@@ -39,48 +46,70 @@ func (fa *FileAnalyzer) AnalyzeFile(
 	repo *git.Repository,
 	filePath string,
 	cutoffDate time.Time,
+	stats *models.FileChangeStats,
 ) (*models.FileAnalysis, error) {
-	// Read file content
-	fullPath := filepath.Join(fa.repoPath, filePath)
-	file, err := os.Open(fullPath)
-	if err != nil {
-		// File might have been deleted - return empty analysis
-		return &models.FileAnalysis{
-			Path:      filePath,
-			Functions: []*models.FunctionStats{},
-		}, nil
-	}
-	defer file.Close()
+	analysis, content := fa.detectFile(filePath)
 
-	// Detect language
-	lang := parser.DetectLanguage(filePath)
-	if !parser.IsSupported(lang) {
-		// Unsupported language - return file-level stats only
-		return &models.FileAnalysis{
-			Path:      filePath,
-			Language:  string(lang),
-			Functions: []*models.FunctionStats{},
-		}, nil
+	// Missing, unsupported, vendored or generated - file-level stats only
+	lang := parser.Language(analysis.Language)
+	if content == nil || !parser.IsSupported(lang) || analysis.Vendored || analysis.Generated {
+		return analysis, nil
 	}
 
 	// Parse functions
 	p := parser.GetParser(lang)
-	functions, err := p.Parse(file)
+	functions, err := p.Parse(bytes.NewReader(content))
 	if err != nil {
 		return nil, fmt.Errorf("parse failed: %w", err)
 	}
+	parser.Annotate(functions, content)
+	parser.MeasureComplexity(functions, content, lang)
 
 	// Build function map for fast lookups
 	fnMap := parser.NewFunctionMap(functions)
 
-	// Map git changes to functions
-	functionStats := fa.mapChangesToFunctions(repo, filePath, fnMap, cutoffDate)
+	// Map git changes to functions. Nothing changed an untouched file in the
+	// window, so its history isn't walked; its functions are at least as old
+	// as the file's last change.
+	if stats != nil && stats.NoChangesInWindow {
+		analysis.Functions = fa.unchangedFunctions(fnMap, stats.LastModified)
+	} else {
+		analysis.Functions = fa.mapChangesToFunctions(repo, filePath, fnMap, cutoffDate)
+	}
+	analysis.Complexity = rollUpComplexity(analysis.Functions)
+
+	return analysis, nil
+}
+
+// DescribeFile detects language and linguist flags without parsing functions
+// (used for files that aren't source code, e.g. docs and configs)
+func (fa *FileAnalyzer) DescribeFile(filePath string) *models.FileAnalysis {
+	analysis, _ := fa.detectFile(filePath)
+	return analysis
+}
+
+// detectFile reads the file and runs language detection on it.
+// Content is nil when the file can't be read.
+func (fa *FileAnalyzer) detectFile(filePath string) (*models.FileAnalysis, []byte) {
+	content, err := os.ReadFile(filepath.Join(fa.repoPath, filePath))
+	if err != nil {
+		// File might have been deleted - fall back to path-based detection
+		return &models.FileAnalysis{
+			Path:      filePath,
+			Language:  string(parser.DetectLanguage(filePath)),
+			Functions: []*models.FunctionStats{},
+		}, nil
+	}
+
+	detection := fa.detector.Detect(filePath, content)
 
 	return &models.FileAnalysis{
 		Path:      filePath,
-		Language:  string(lang),
-		Functions: functionStats,
-	}, nil
+		Language:  string(detection.Language),
+		Vendored:  detection.Vendored,
+		Generated: detection.Generated,
+		Functions: []*models.FunctionStats{},
+	}, content
 }
 
 // mapChangesToFunctions uses git log to find which lines changed, then maps to functions
@@ -90,24 +119,14 @@ func (fa *FileAnalyzer) mapChangesToFunctions(
 	fnMap *parser.FunctionMap,
 	cutoffDate time.Time,
 ) []*models.FunctionStats {
-	// Initialize stats for each function
-	statsMap := make(map[string]*models.FunctionStats)
-	for _, fn := range fnMap.GetAll() {
-		statsMap[fn.Name] = &models.FunctionStats{
-			Name:         fn.Name,
-			LineStart:    fn.LineStart,
-			LineEnd:      fn.LineEnd,
-			ChangesByDay: make(map[int]int),
-			LastModified: time.Time{},
-		}
-	}
+	statsMap := fa.newFunctionStats(fnMap)
 
 	// Get file history
 	commits, err := repo.Log(&git.LogOptions{
 		FileName: &filePath,
 	})
 	if err != nil {
-		return fa.statsToSlice(statsMap)
+		return fa.statsToSlice(fnMap, statsMap)
 	}
 
 	// Process each commit
@@ -125,11 +144,21 @@ func (fa *FileAnalyzer) mapChangesToFunctions(
 		// Extract changed line numbers from patch
 		changedLines := fa.extractChangedLines(patch)
 
+		isFix := fa.commits != nil && fa.commits.Classify(c.Message) == models.CommitFix
+		touched := make(map[*parser.Function]bool)
+
 		// Map each changed line to its function
 		for _, line := range changedLines {
 			fn := fnMap.FindByLine(line)
 			if fn != nil {
-				stats := statsMap[fn.Name]
+				stats := statsMap[fn]
+
+				// Fixes count once per commit, not per line
+				if isFix && !touched[fn] {
+					stats.FixCommits++
+					stats.FixesByDay[int(time.Since(c.Author.When).Hours()/24)]++
+				}
+				touched[fn] = true
 				stats.TotalChanges++
 				
 				// Update last modified
@@ -146,7 +175,54 @@ func (fa *FileAnalyzer) mapChangesToFunctions(
 		return nil
 	})
 
-	return fa.statsToSlice(statsMap)
+	return fa.statsToSlice(fnMap, statsMap)
+}
+
+// newFunctionStats initializes stats for each function
+// Keyed by unit, not name: Python classes all have __init__, notebooks redefine functions
+func (fa *FileAnalyzer) newFunctionStats(fnMap *parser.FunctionMap) map[*parser.Function]*models.FunctionStats {
+	statsMap := make(map[*parser.Function]*models.FunctionStats)
+	for _, fn := range fnMap.GetAll() {
+		stats := &models.FunctionStats{
+			Name:         fn.Name,
+			Type:         string(fn.Type),
+			LineStart:    fn.LineStart,
+			LineEnd:      fn.LineEnd,
+			ChangesByDay: make(map[int]int),
+			FixesByDay:   make(map[int]int),
+			LastModified: time.Time{},
+			Complexity:   toComplexity(fn.Complexity),
+		}
+
+		if fn.Annotation != "" {
+			stats.Suppression = &models.Suppression{
+				Kind:   models.SuppressionKind(fn.Annotation),
+				Source: models.SuppressionSourceAnnotation,
+				Reason: fn.AnnotationNote,
+			}
+		}
+
+		// Notebook units: show lines within the cell, not JSON lines
+		if fn.Cell > 0 {
+			stats.Cell = fn.Cell
+			stats.LineStart = fn.CellLineStart
+			stats.LineEnd = fn.CellLineEnd
+		}
+
+		statsMap[fn] = stats
+	}
+
+	return statsMap
+}
+
+// unchangedFunctions lists the functions of a file nothing changed in the
+// window, dated by the file's last change
+func (fa *FileAnalyzer) unchangedFunctions(fnMap *parser.FunctionMap, lastModified time.Time) []*models.FunctionStats {
+	statsMap := fa.newFunctionStats(fnMap)
+	for _, stats := range statsMap {
+		stats.LastModified = lastModified
+	}
+	return fa.statsToSlice(fnMap, statsMap)
 }
 
 // getCommitPatch gets the diff for a specific file in a commit
@@ -164,7 +240,35 @@ func (fa *FileAnalyzer) getCommitPatch(repo *git.Repository, commit *object.Comm
 		return "", err
 	}
 
-	return patch.String(), nil
+	// Only keep this file's hunks - other files' line numbers mean nothing here
+	for _, fp := range patch.FilePatches() {
+		_, to := fp.Files()
+		if to == nil || to.Path() != filePath {
+			continue
+		}
+
+		var buf bytes.Buffer
+		encoder := diff.NewUnifiedEncoder(&buf, diff.DefaultContextLines)
+		if err := encoder.Encode(&filePatch{file: fp}); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	return "", nil
+}
+
+// filePatch narrows a commit patch down to a single file
+type filePatch struct {
+	file diff.FilePatch
+}
+
+func (p *filePatch) FilePatches() []diff.FilePatch {
+	return []diff.FilePatch{p.file}
+}
+
+func (p *filePatch) Message() string {
+	return ""
 }
 
 // extractChangedLines parses git diff patch format to get line numbers
@@ -199,11 +303,11 @@ func (fa *FileAnalyzer) extractChangedLines(patch string) []int {
 	return lines
 }
 
-// statsToSlice converts map to slice
-func (fa *FileAnalyzer) statsToSlice(statsMap map[string]*models.FunctionStats) []*models.FunctionStats {
+// statsToSlice converts map to slice, in line order
+func (fa *FileAnalyzer) statsToSlice(fnMap *parser.FunctionMap, statsMap map[*parser.Function]*models.FunctionStats) []*models.FunctionStats {
 	result := make([]*models.FunctionStats, 0, len(statsMap))
-	for _, stats := range statsMap {
-		result = append(result, stats)
+	for _, fn := range fnMap.GetAll() {
+		result = append(result, statsMap[fn])
 	}
 	return result
-}
\ No newline at end of file
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"log"
 	"os"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 
 	"github.com/richd0tcom/fire-sight/internal/models"
@@ -27,7 +29,11 @@ func NewGitAnalyzer(tempDir string) *GitAnalyzer {
 
 func (ga *GitAnalyzer) cloneRepo(ctx context.Context, repoURL string, opts models.AnalyzeOptions) (*git.Repository, string, error) {
 
-	repoPath := fmt.Sprintf("%s/repo-%d", ga.tempDir, time.Now().Unix())
+	// Unique per call: concurrent analyses must not clone into the same directory
+	repoPath, err := os.MkdirTemp(ga.tempDir, "repo-*")
+	if err != nil {
+		return nil, "", err
+	}
 
 	cloneOpts:= git.CloneOptions{
 		URL: repoURL,
@@ -44,6 +50,7 @@ func (ga *GitAnalyzer) cloneRepo(ctx context.Context, repoURL string, opts model
 
 	repo, err := git.PlainCloneContext(ctx, repoPath, false, &cloneOpts)
 	if err != nil {
+		os.RemoveAll(repoPath)
 		return nil, "", err
 	}
 
@@ -79,12 +86,39 @@ func (ga *GitAnalyzer) AnalyzeRepository(ctx context.Context, repoUrl string, op
 		return nil, fmt.Errorf("walk repo tree failed: %w", err)
 	}
 
-	result, err := ga.parseGitHistory(ctx, repo, repoPath, opts, fStats)
+	// Dead-code rules: request rules first, then the repo's .firesight.yaml
+	repoRules, err := LoadRulesFile(repoPath)
+	if err == nil {
+		_, err = NewRuleEngine(repoRules)
+	}
+	if err != nil {
+		log.Printf("ignoring repo rules file: %v", err)
+		repoRules = nil
+	}
+	rules := MergeRules(opts.Rules, repoRules)
+
+	// Both rule sets were validated, so the merged patterns compile
+	commits, err := NewCommitClassifier(rules)
+	if err != nil {
+		return nil, fmt.Errorf("commit rules failed: %w", err)
+	}
+
+	result, err := ga.parseGitHistory(ctx, repo, repoPath, opts, fStats, commits)
 
 	if err != nil {
 		return nil, fmt.Errorf("parse history failed: %w", err)
 	}
 
+	result.Rules = rules
+	result.HeatModel = opts.HeatModel
+	result.SortBy = opts.SortBy
+	result.Series = opts.Series
+
+	ignoreFile, err := LoadIgnoreFile(repoPath)
+	if err != nil {
+		log.Printf("ignoring repo ignore file: %v", err)
+	}
+
 
 
 
@@ -92,28 +126,75 @@ func (ga *GitAnalyzer) AnalyzeRepository(ctx context.Context, repoUrl string, op
 	cutoffDate := time.Now().AddDate(0, 0, -opts.TimeRangeDays)
 
 	fileAnalyzer := NewFileAnalyzer(repoPath)
+	fileAnalyzer.commits = commits
+
+	// Static reachability for Go code - must run while the clone still exists
+	callGraph, err := BuildGoCallGraph(ctx, repoPath)
+	if err != nil {
+		log.Printf("call graph failed: %v", err)
+	}
 
+	// Reference counts for JS/TS and Python - every file may hold a caller
+	allPaths := make([]string, 0, len(fStats))
+	for path := range fStats {
+		allPaths = append(allPaths, path)
+	}
+	symbolIndex := BuildSymbolIndex(repoPath, allPaths, fileAnalyzer.detector)
+	entrypoints := BuildEntrypointClassifier(repoPath, allPaths, fileAnalyzer.detector)
+
+	// Coverage reports name files their own way - map them onto repo paths
+	coverage, err := ParseCoverageReports(opts.Coverage)
+	if err != nil {
+		return nil, fmt.Errorf("coverage failed: %w", err)
+	}
+	coverageByPath := coverage.resolve(allPaths)
+
+	profiles, err := ParseProfiles(opts.Profiles)
+	if err != nil {
+		return nil, fmt.Errorf("profiles failed: %w", err)
+	}
 	
 
 	for filePath := range result.FileStats {
-		// Skip non-source files (docs, configs, etc.)
+		// .firesightignore: still listed, never parsed or scored
+		if pattern, ignored := ignoreFile.Match(filePath); ignored {
+			analysis := fileAnalyzer.DescribeFile(filePath)
+			analysis.Suppression = &models.Suppression{
+				Kind:   models.SuppressionIgnore,
+				Source: models.SuppressionSourceIgnoreFile,
+				Reason: pattern,
+			}
+			result.FileFunctionAnalyses[filePath] = analysis
+			continue
+		}
+
+		// Skip function parsing for non-source files (docs, configs, etc.)
 		if !isSourceFile(filePath) {
+			result.FileFunctionAnalyses[filePath] = fileAnalyzer.DescribeFile(filePath)
 			continue
 		}
 
-		analysis, err := fileAnalyzer.AnalyzeFile(ctx, repo, filePath, cutoffDate)
+		analysis, err := fileAnalyzer.AnalyzeFile(ctx, repo, filePath, cutoffDate, result.FileStats[filePath])
 		if err != nil {
-			fmt.Println(err)
+			log.Printf("analyze %s failed: %v", filePath, err)
 			continue
 		}
 		
+		applyReachability(analysis, callGraph)
+		applyReferences(analysis, symbolIndex)
+		applyCoverage(analysis, coverageByPath[filePath])
+		entrypoints.classify(analysis)
 		result.FileFunctionAnalyses[filePath] = analysis
 	}
 
+	// Both need every file's units, so run after the loop
+	profiles.apply(result.FileFunctionAnalyses, allPaths)
+	applyOrphans(result.FileFunctionAnalyses, BuildImportGraph(repoPath, allPaths, fileAnalyzer.detector))
+
 	return result, nil
 }
 
-func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository, repoURL string, opts models.AnalyzeOptions, baseTreeStats map[string]bool) (*models.AnalysisResult, error) {
+func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository, repoURL string, opts models.AnalyzeOptions, baseTreeStats map[string]bool, commits *CommitClassifier) (*models.AnalysisResult, error) {
 	branch := opts.Branch
 	if branch == "" {
 		branch = "main"
@@ -140,6 +221,8 @@ func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository
 	cutoffDate := time.Now().AddDate(0, 0, -opts.TimeRangeDays)
 
 	fileStats:= make(map[string]*models.FileChangeStats)
+	authorLastCommit := make(map[string]time.Time)
+	coupled := make(coChanges)
 	commitCount := 0
 
 	err = commitIter.ForEach(func(c *object.Commit) error {
@@ -156,11 +239,18 @@ func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository
 
 		commitCount++
 
+		if c.Author.When.After(authorLastCommit[c.Author.Name]) {
+			authorLastCommit[c.Author.Name] = c.Author.When
+		}
+
 		stats, err := c.Stats()
 		if err != nil {
 			return err
 		}
 
+		kind := commits.Classify(c.Message)
+		changed := make([]string, 0, len(stats))
+
 		for _, gitFileStat := range stats {
 			path:= gitFileStat.Name
 
@@ -169,28 +259,42 @@ func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository
 				if strings.Contains(path, "=>") {
 					path = strings.TrimSpace(strings.Split(path, "=>")[1])
 				}	
-				fileStats[path] = &models.FileChangeStats{
-					FilePath:          path,
-					ChangesByDay:      make(map[int]int),
-					UniqueAuthors:     make(map[string]int),
-					FirstSeen:         c.Author.When,
-				}
+				fileStats[path] = newFileChangeStats(path)
+				fileStats[path].FirstSeen = c.Author.When
 			}
 
 			fs := fileStats[path]
+			changed = append(changed, path)
 
 			fs.TotalChanges ++
 
 			if c.Author.When.After(fs.LastModified) {
 				fs.LastModified = c.Author.When
 			}
+			// Commits come newest first
+			if c.Author.When.Before(fs.FirstSeen) {
+				fs.FirstSeen = c.Author.When
+			}
 
 			dayOffset:= int(time.Since(c.Author.When).Hours() / 24)
 			fs.ChangesByDay[dayOffset]++
 
+			fs.CommitKinds[kind]++
+			if kind == models.CommitFix {
+				fs.FixCommits++
+				fs.FixesByDay[dayOffset]++
+			}
+
+			fs.LinesAdded += gitFileStat.Addition
+			fs.LinesRemoved += gitFileStat.Deletion
+			fs.AddedByDay[dayOffset] += gitFileStat.Addition
+			fs.RemovedByDay[dayOffset] += gitFileStat.Deletion
+
 			fs.UniqueAuthors[c.Author.Name]++
 		}
 
+		coupled.add(changed)
+
 
 
 		return nil
@@ -199,18 +303,33 @@ func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository
 	if err != nil {
 		return nil, fmt.Errorf("iterate commits failed: %w", err)
 	}
-	println("Commits processed: ", commitCount)
-	println("Files processed: ", len(fileStats))
-	println("file stats", fileStats)
 
 	//filter stats to current tree
 	for filePath := range fileStats {
 		if _, exists := baseTreeStats[filePath]; !exists {
-			println("Deleting file: ", filePath)
 			delete(fileStats, filePath)
 		}
 	}
 
+	// Files the window never touched are the coldest of all; list them too,
+	// dated by their last change in the full history
+	untouched := []string{}
+	for filePath := range baseTreeStats {
+		if _, exists := fileStats[filePath]; !exists {
+			untouched = append(untouched, filePath)
+		}
+	}
+	lastChanged, err := lastChanges(ctx, repo, ref.Hash(), cutoffDate, untouched)
+	if err != nil {
+		return nil, fmt.Errorf("walk older history failed: %w", err)
+	}
+	for _, filePath := range untouched {
+		fs := newFileChangeStats(filePath)
+		fs.LastModified = lastChanged[filePath]
+		fs.NoChangesInWindow = true
+		fileStats[filePath] = fs
+	}
+
 	return &models.AnalysisResult{
 		RepoURL:      repoURL,
 		Branch:       branch,
@@ -218,10 +337,101 @@ func (ga *GitAnalyzer) parseGitHistory(ctx context.Context, repo *git.Repository
 		CommitCount:  commitCount,
 		FileStats:    fileStats,
 		TimeRangeDays: opts.TimeRangeDays,
+		AuthorLastCommit: authorLastCommit,
+		Couplings:     coupled.couplings(fileStats),
 	}, nil
 }
 
+func newFileChangeStats(path string) *models.FileChangeStats {
+	return &models.FileChangeStats{
+		FilePath:          path,
+		ChangesByDay:      make(map[int]int),
+		CommitKinds:       make(map[models.CommitKind]int),
+		FixesByDay:        make(map[int]int),
+		AddedByDay:        make(map[int]int),
+		RemovedByDay:      make(map[int]int),
+		UniqueAuthors:     make(map[string]int),
+	}
+}
+
+// lastChanges finds when each path was last changed before cutoffDate,
+// newest commits first, stopping once every path is dated. Paths no
+// commit on the branch touches are left out.
+func lastChanges(ctx context.Context, repo *git.Repository, from plumbing.Hash, cutoffDate time.Time, paths []string) (map[string]time.Time, error) {
+	found := make(map[string]time.Time)
+	if len(paths) == 0 {
+		return found, nil
+	}
+
+	pending := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		pending[path] = true
+	}
+
+	commitIter, err := repo.Log(&git.LogOptions{
+		From:  from,
+		Order: git.LogOrderCommitterTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		// The window's commits didn't touch these files
+		if !c.Author.When.Before(cutoffDate) {
+			return nil
+		}
+
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+
+		// First commit: diff against nothing, everything was added
+		var parentTree *object.Tree
+		if parent, err := c.Parent(0); err == nil {
+			if parentTree, err = parent.Tree(); err != nil {
+				return err
+			}
+		}
+
+		changes, err := object.DiffTreeContext(ctx, parentTree, tree)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			path := change.To.Name
+			if pending[path] {
+				found[path] = c.Author.When
+				delete(pending, path)
+			}
+		}
+
+		if len(pending) == 0 {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
 func isSourceFile(path string) bool {
+	// Notebooks are JSON on disk but hold real code
+	if strings.HasSuffix(strings.ToLower(path), ".ipynb") {
+		return true
+	}
+
 	// Skip common non-source files
 	excludePatterns := []string{
 		".md", ".txt", ".json", ".yaml", ".yml",
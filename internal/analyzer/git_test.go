@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// testRepo commits a small Go module to a fresh local repository
+func testRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.21\n",
+		"main.go":        "package main\n\nfunc main() {\n\trun()\n}\n",
+		"run.go":         "package main\n\nfunc run() {\n\tif true {\n\t}\n}\n",
+		"docs/README.md": "# app\n",
+	}
+	for i, name := range []string{"go.mod", "main.go", "run.go", "docs/README.md"} {
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(files[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+
+		when := time.Now().AddDate(0, 0, -10+i)
+		_, err := wt.Commit(fmt.Sprintf("add %s", name), &git.CommitOptions{
+			Author: &object.Signature{Name: "alice", Email: "alice@example.com", When: when},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestAnalyzeRepositoryOverlapping(t *testing.T) {
+	origin := testRepo(t)
+	tempDir := t.TempDir()
+	ga := NewGitAnalyzer(tempDir)
+
+	const analyses = 4
+	results := make([]*models.AnalysisResult, analyses)
+	errs := make([]error, analyses)
+
+	// Started together, the clones land within the same second
+	var ready, done sync.WaitGroup
+	ready.Add(1)
+	for i := 0; i < analyses; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			ready.Wait()
+			results[i], errs[i] = ga.AnalyzeRepository(context.Background(), origin, models.AnalyzeOptions{Branch: "master", TimeRangeDays: 30})
+		}()
+	}
+	ready.Done()
+	done.Wait()
+
+	for i := 0; i < analyses; i++ {
+		if errs[i] != nil {
+			t.Errorf("analysis %d: %v", i, errs[i])
+			continue
+		}
+		for _, path := range []string{"main.go", "run.go", "docs/README.md"} {
+			if results[i].FileStats[path] == nil {
+				t.Errorf("analysis %d: no stats for %s", i, path)
+			}
+		}
+	}
+
+	// Every clone is gone once its analysis returns
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d clone directories left in the temp dir", len(entries))
+	}
+}
@@ -1,7 +1,6 @@
 package analyzer
 
 import (
-	"math"
 	"time"
 
 	"github.com/richd0tcom/fire-sight/internal/models"
@@ -9,7 +8,7 @@ import (
 
 
 const (
-	DecayRate = 0.01
+	DecayRate = 0.01 // default exponential model, see NewHeatModel
 	MinScore = 1.0
 )
 
@@ -19,43 +18,55 @@ func NewHeatCalculator() *HeatCalculator {
 	return &HeatCalculator{}
 }
 
-func (hc *HeatCalculator) CalculateHeatScores(result *models.AnalysisResult) []models.HeatScore {
+func (hc *HeatCalculator) CalculateHeatScores(result *models.AnalysisResult, model HeatModel) []models.HeatScore {
 	scores := make([]models.HeatScore, 0, len(result.FileStats))
 
 	now:= time.Now()
 
 	// First pass: calculate raw scores
-	maxRawScore := 0.0
-	rawScores := make(map[string]float64)
+	paths := make([]string, 0, len(result.FileStats))
+	rawScores := make([]float64, 0, len(result.FileStats))
+	rawDefects := make([]float64, 0, len(result.FileStats))
+	ignored := make([]bool, 0, len(result.FileStats))
 
 	for path, fs := range result.FileStats {
-		rawScore := hc.calculateRawScore(fs)
-		rawScores[path] = rawScore
-		
-		if rawScore > maxRawScore {
-			maxRawScore = rawScore
-		}
+		paths = append(paths, path)
+		rawScores = append(rawScores, hc.calculateRawScore(fs, model))
+		rawDefects = append(rawDefects, rawDefectScore(fs.FixesByDay, model))
+
+		// Ignored and untouched files don't set the scale for everyone else
+		ignored = append(ignored, isIgnoredFile(result, path) || fs.NoChangesInWindow)
 	}
 
+	normalizedScores := model.Normalize(rawScores, ignored)
+	defectHeats := defectHeat(rawDefects, ignored, model)
 
-	for path, stats := range result.FileStats {
-		rawScore := rawScores[path]
-		
-		// Normalize: (raw / max) * 100
-		// This ensures hottest file = 100, coldest = relative to that
-		normalizedScore := MinScore
-		if maxRawScore > 0 {
-			normalizedScore = (rawScore / maxRawScore) * 100
-		}
+	for i, path := range paths {
+		stats := result.FileStats[path]
 
 		daysSinceEdit := int(now.Sub(stats.LastModified).Hours() / 24)
+		if stats.LastModified.IsZero() {
+			// Not on the branch's history: at least as old as the window
+			daysSinceEdit = result.TimeRangeDays
+		}
+
+		recent, older := splitChanges(stats.ChangesByDay, result.TimeRangeDays)
 
 		scores = append(scores, models.HeatScore{
 			Path:          path,
-			Score:         normalizedScore,
-			ChangeFreq:    hc.calculateChangeFrequency(stats.TotalChanges, daysSinceEdit),
+			Score:         normalizedScores[i],
+			RawScore:      rawScores[i],
+			ChangeFreq:    hc.calculateChangeFrequency(stats.TotalChanges, stats.ChangesByDay),
 			DaysSinceEdit: daysSinceEdit,
 			TotalFileChanges: stats.TotalChanges,
+			LinesAdded:    stats.LinesAdded,
+			LinesRemoved:  stats.LinesRemoved,
+			FixCommits:    stats.FixCommits,
+			DefectHeat:    defectHeats[i],
+			Trend:         classifyTrend(recent, older),
+			RecentChanges: recent,
+			OlderChanges:  older,
+			NoChangesInWindow: stats.NoChangesInWindow,
 		})
 	}
 
@@ -64,133 +75,274 @@ func (hc *HeatCalculator) CalculateHeatScores(result *models.AnalysisResult) []m
 	return scores
 }
 
-func (hc *HeatCalculator) calculateRawScore(fs *models.FileChangeStats) float64 {
+func isIgnoredFile(result *models.AnalysisResult, path string) bool {
+	analysis := result.FileFunctionAnalyses[path]
+	return analysis != nil && analysis.Suppression.Ignored()
+}
+
+func (hc *HeatCalculator) calculateRawScore(fs *models.FileChangeStats, model HeatModel) float64 {
 	score:= 0.0
 
 	for dayOffset, changeCount := range fs.ChangesByDay {
-		weight:= getTimeDecay(dayOffset)
+		weight:= model.Weight(dayOffset)
+		churn := fs.AddedByDay[dayOffset] + fs.RemovedByDay[dayOffset]
 
-		score += float64(changeCount) * weight
+		score += model.Amount(changeCount, churn) * weight
 	}
 
-	authorBonus:= getAuthorBonus(len(fs.UniqueAuthors))
+	authorBonus:= model.AuthorBonus(len(fs.UniqueAuthors))
 	score *= (1.0 + authorBonus)
 
 	return score
 }
 
-//returns num of changes per week
-func (hc *HeatCalculator) calculateChangeFrequency(totalChanges int, days int) float64 {
-	if days == 0 {
+// rawDefectScore: heat from fix commits alone, same age weights
+func rawDefectScore(fixesByDay map[int]int, model HeatModel) float64 {
+	score := 0.0
+	for dayOffset, fixes := range fixesByDay {
+		score += float64(fixes) * model.Weight(dayOffset)
+	}
+	return score
+}
+
+// defectHeat normalizes like heat, but no fixes means no defect heat,
+// whatever the normalization would make of a zero
+func defectHeat(rawDefects []float64, ignored []bool, model HeatModel) []float64 {
+	scores := model.Normalize(rawDefects, ignored)
+	for i, raw := range rawDefects {
+		if raw == 0 {
+			scores[i] = 0
+		}
+	}
+	return scores
+}
+
+//returns num of changes per week, over the span from the oldest change in
+//the window until now (a week at least, so one fresh commit isn't "7 a week")
+func (hc *HeatCalculator) calculateChangeFrequency(totalChanges int, changesByDay map[int]int) float64 {
+	if totalChanges == 0 {
 		return 0
 	}
-	
-	weeks := float64(days) / 7.0
+
+	span := 0
+	for dayOffset := range changesByDay {
+		span = max(span, dayOffset+1)
+	}
+
+	weeks := float64(max(span, 7)) / 7.0
 	return float64(totalChanges) / weeks
 }
 
+// Trend thresholds: one half needs 1.5x the other, and 2 more changes,
+// so a single commit doesn't flip a quiet file to "rising"
+const (
+	trendRatio    = 1.5
+	trendMinDelta = 2
+)
+
+// splitChanges counts changes in the recent and the older half of the window
+func splitChanges(changesByDay map[int]int, windowDays int) (recent, older int) {
+	half := windowDays / 2
+	if half <= 0 {
+		half = 90
+	}
+
+	for dayOffset, count := range changesByDay {
+		if dayOffset < half {
+			recent += count
+		} else {
+			older += count
+		}
+	}
+	return recent, older
+}
+
+func classifyTrend(recent, older int) models.Trend {
+	switch {
+	case float64(recent) >= trendRatio*float64(older) && recent-older >= trendMinDelta:
+		return models.TrendRising
+	case float64(older) >= trendRatio*float64(recent) && older-recent >= trendMinDelta:
+		return models.TrendCooling
+	default:
+		return models.TrendSteady
+	}
+}
 
 
-func (hc *HeatCalculator) isLikelyDeadCode(stats *models.FileChangeStats, now time.Time) bool {
+
+// isLikelyDeadCode returns the file verdict and every signal that fired
+func (hc *HeatCalculator) isLikelyDeadCode(stats *models.FileChangeStats, analysis *models.FileAnalysis, now time.Time, policy *DeadCodePolicy) (bool, []models.DeadCodeReason) {
+	reasons := []models.DeadCodeReason{}
+
 	// Signal 1: No changes in 6+ months
-	sixMonthsAgo := now.AddDate(0, -6, 0)
-	noRecentChanges := stats.LastModified.Before(sixMonthsAgo)
+	if stats.LastModified.Before(now.AddDate(0, 0, -policy.InactiveDays)) {
+		reasons = append(reasons, models.DeadCodeReasonInactive)
+	}
 
 	// Signal 2: Very few total changes (< 3)
-	fewChanges := stats.TotalChanges < 3
+	if stats.TotalChanges < policy.MinChanges {
+		reasons = append(reasons, models.DeadCodeReasonFewChanges)
+	}
 
 	// Signal 3: Single author (might be abandoned experiment)
-	singleAuthor := len(stats.UniqueAuthors) == 1
-
-	// Dead code if 2 out of 3 signals present
-	signalCount := 0
-	if noRecentChanges {
-
-		signalCount++
+	if len(stats.UniqueAuthors) == 1 {
+		reasons = append(reasons, models.DeadCodeReasonSingleAuthor)
 	}
-	if fewChanges {
-		signalCount++
+
+	// Signal 4: No test runs it (only with a coverage report)
+	if analysis != nil && analysis.Coverage != nil && *analysis.Coverage == 0 {
+		reasons = append(reasons, models.DeadCodeReasonUncovered)
 	}
-	if singleAuthor {
-		signalCount++
+
+	// Signal 5: Nothing imports it
+	if analysis != nil && analysis.Orphan != nil && *analysis.Orphan {
+		reasons = append(reasons, models.DeadCodeReasonOrphan)
 	}
 
-	return signalCount >= 2
-}
+	// It shows up in a production profile - whatever git says, it runs
+	if analysis != nil && hasSamples(analysis.RuntimeShare) {
+		return false, reasons
+	}
 
-func getTimeDecay(daysSinceEdit int) float64 {
-	return math.Exp(-DecayRate * float64(daysSinceEdit))
-}
+	if analysis != nil && analysis.Suppression != nil {
+		return false, reasons
+	}
 
-func getAuthorBonus(numUniqueAuthors int) float64 {
-	return math.Min(float64(numUniqueAuthors)*0.1, 0.5)
+	// Dead code if 2 out of 3 signals present (by default)
+	return policy.decide(reasons), reasons
 }
 
-
-func (hc *HeatCalculator) calculateRawFunctionScore(stats *models.FunctionStats) float64 {
+func (hc *HeatCalculator) calculateRawFunctionScore(stats *models.FunctionStats, model HeatModel) float64 {
 	score := 0.0
 
+	// Function changes are counted per changed line, so they are churn already
 	for dayOffset, changeCount := range stats.ChangesByDay {
-		weight:= getTimeDecay(dayOffset)
+		weight:= model.Weight(dayOffset)
 
-		score += float64(changeCount) * weight
+		score += model.Amount(changeCount, changeCount) * weight
 	}
 
 	return score
 }
 
 // IsLikelyDeadFunctionCode determines if a function is probably unused
-func (hc *HeatCalculator) isLikelyDeadFunctionCode(stats *models.FunctionStats, now time.Time) bool {
+func (hc *HeatCalculator) isLikelyDeadFunctionCode(stats *models.FunctionStats, now time.Time, policy *DeadCodePolicy) bool {
+	if policy.Never || stats.Suppression != nil || hasSamples(stats.RuntimeShare) || policy.excludes(stats.Role) {
+		return false
+	}
+
+	// Reachable code is alive however cold it is
+	if stats.Reachable != nil && *stats.Reachable {
+		return false
+	}
+
+	reasons := []models.DeadCodeReason{}
+
 	// Signal 1: No changes in 6+ months
-	sixMonthsAgo := now.AddDate(0, -6, 0)
-	noRecentChanges := stats.LastModified.Before(sixMonthsAgo)
+	inactive := stats.LastModified.Before(now.AddDate(0, 0, -policy.InactiveDays))
+	if inactive {
+		reasons = append(reasons, models.DeadCodeReasonInactive)
+	}
 
 	// Signal 2: Very few total changes (< 2)
-	fewChanges := stats.TotalChanges < 2
+	if stats.TotalChanges < policy.MinChanges {
+		reasons = append(reasons, models.DeadCodeReasonFewChanges)
+	}
+
+	// Signal 3: No test runs it (only with a coverage report)
+	if stats.Coverage != nil && *stats.Coverage == 0 {
+		reasons = append(reasons, models.DeadCodeReasonUncovered)
+	}
+
+	// Signal 4: The Go call graph can't reach it. Only for inactive code:
+	// unreachable code someone is still editing is more likely not wired up
+	// yet, or called in a way the stubbed imports hide
+	if stats.Reachable != nil && !*stats.Reachable && inactive {
+		reasons = append(reasons, models.DeadCodeReasonUnreachable)
+	}
 
-	// Dead code if both signals present
-	return noRecentChanges && fewChanges
+	// Signal 5: Nothing in the repo mentions it (JS/TS, Python symbol index).
+	// Same rule as unreachable: a fresh function may not be wired up yet
+	if stats.References != nil && *stats.References == 0 && inactive {
+		reasons = append(reasons, models.DeadCodeReasonUnreferenced)
+	}
+
+	// Dead code if two signals present (by default)
+	return policy.decide(reasons)
 }
 
-func (hc *HeatCalculator) CalculateFunctionHeatScore(stats []*models.FunctionStats) []*models.FunctionNode {
+func (hc *HeatCalculator) CalculateFunctionHeatScore(stats []*models.FunctionStats, policy *DeadCodePolicy, model HeatModel, windowDays int) []*models.FunctionNode {
 	result := make([]*models.FunctionNode, 0, len(stats))
 
-	maxRawScore := 0.0
-	rawScores := make(map[string]float64)
+	rawScores := make([]float64, len(stats))
+	rawDefects := make([]float64, len(stats))
+	ignored := make([]bool, len(stats))
 
-	for _, stat := range stats {
-		rawScore := hc.calculateRawFunctionScore(stat)
-
-		rawScores[stat.Name] = rawScore
-		
-		if rawScore > maxRawScore {
-			maxRawScore = rawScore
-		}
+	for i, stat := range stats {
+		rawScores[i] = hc.calculateRawFunctionScore(stat, model)
+		rawDefects[i] = rawDefectScore(stat.FixesByDay, model)
+		ignored[i] = stat.Suppression.Ignored()
 	}
 
-
 	//Normalize
-	for _, stat := range stats {
-		rawScore := rawScores[stat.Name]
-		
-		// Normalize: (raw / max) * 100
-		// This ensures hottest file = 100, coldest = relative to that
-		normalizedScore := MinScore
-		if maxRawScore > 0 {
-			normalizedScore = (rawScore / maxRawScore) * 100
+	normalizedScores := model.Normalize(rawScores, ignored)
+	defectHeats := defectHeat(rawDefects, ignored, model)
+
+	complexities := make([]*models.Complexity, len(stats))
+	for i, stat := range stats {
+		complexities[i] = stat.Complexity
+	}
+	hotspots := hotspotScores(rawScores, complexities, ignored, model)
+
+	now := time.Now()
+	for i, stat := range stats {
+		daysSinceEdit := int(now.Sub(stat.LastModified).Hours() / 24)
+		if stat.LastModified.IsZero() {
+			// No commit in the window touched it: at least as old as the window
+			daysSinceEdit = windowDays
 		}
+		recent, older := splitChanges(stat.ChangesByDay, windowDays)
+		confidence, signals := hc.deadCodeConfidence(confidenceInput{
+			daysSinceEdit: daysSinceEdit,
+			changes:       stat.TotalChanges,
+			reachable:     stat.Reachable,
+			references:    stat.References,
+			coverage:      stat.Coverage,
+			runtimeShare:  stat.RuntimeShare,
+			role:          stat.Role,
+			suppressed:    stat.Suppression != nil,
+		}, policy)
 
 		result = append(result, &models.FunctionNode{
 			Name:            stat.Name,
+			Type:            stat.Type,
+			Role:            stat.Role,
+			Cell:            stat.Cell,
 			LineStart:       stat.LineStart,
 			LineEnd:         stat.LineEnd,
 			LastModified:    stat.LastModified,
 			HeatScore:       &models.HeatScore{
-				Score:         normalizedScore,
-				ChangeFreq:    hc.calculateChangeFrequency(stat.TotalChanges, int(time.Since(stat.LastModified).Hours()/24)),
-				DaysSinceEdit: int(time.Since(stat.LastModified).Hours()/24),
+				Score:         normalizedScores[i],
+				RawScore:      rawScores[i],
+				ChangeFreq:    hc.calculateChangeFrequency(stat.TotalChanges, stat.ChangesByDay),
+				FixCommits:    stat.FixCommits,
+				DefectHeat:    defectHeats[i],
+				Trend:         classifyTrend(recent, older),
+				RecentChanges: recent,
+				OlderChanges:  older,
+				DaysSinceEdit: daysSinceEdit,
 			},
-			IsDeadCode:      hc.isLikelyDeadFunctionCode(stat, time.Now()),
+			IsDeadCode:      hc.isLikelyDeadFunctionCode(stat, now, policy),
+			DeadCodeRule:    policy.Rule,
+			DeadCodeConfidence: confidence,
+			DeadCodeSignals: signals,
+			Reachable:       stat.Reachable,
+			References:      stat.References,
+			Coverage:        stat.Coverage,
+			RuntimeShare:    stat.RuntimeShare,
+			Suppression:     stat.Suppression,
+			Complexity:      stat.Complexity,
+			HotspotScore:    hotspots[i],
 		})
 	}
 
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func TestFunctionDaysSinceEditUntouched(t *testing.T) {
+	hc := NewHeatCalculator()
+	rules, _ := NewRuleEngine(nil)
+	policy := rules.Policy("main.go", "go", functionNode)
+	model, err := NewHeatModel(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := []*models.FunctionStats{
+		{Name: "touched", TotalChanges: 1, LastModified: time.Now().AddDate(0, 0, -3), ChangesByDay: map[int]int{3: 1}},
+		{Name: "untouched", ChangesByDay: map[int]int{}},
+	}
+
+	nodes := hc.CalculateFunctionHeatScore(stats, policy, model, 90)
+
+	if got := nodes[0].HeatScore.DaysSinceEdit; got != 3 {
+		t.Errorf("touched: daysSinceEdit = %d, want 3", got)
+	}
+	// A zero time would put it some 106751 days back
+	if got := nodes[1].HeatScore.DaysSinceEdit; got != 90 {
+		t.Errorf("untouched: daysSinceEdit = %d, want the window (90)", got)
+	}
+}
+
+func TestUnreferencedFunctionNeedsInactivity(t *testing.T) {
+	hc := NewHeatCalculator()
+	rules, _ := NewRuleEngine(nil)
+	policy := rules.Policy("src/app.js", "javascript", functionNode)
+	now := time.Now()
+
+	none, some := 0, 3
+	tests := []struct {
+		name       string
+		references *int
+		edited     time.Time
+		changes    int
+		want       bool
+	}{
+		{"unreferenced, added yesterday", &none, now.AddDate(0, 0, -1), 1, false},
+		{"unreferenced and inactive", &none, now.AddDate(-1, 0, 0), 5, true},
+		{"referenced, inactive, few changes", &some, now.AddDate(-1, 0, 0), 1, true},
+		{"referenced and inactive", &some, now.AddDate(-1, 0, 0), 5, false},
+	}
+
+	for _, tt := range tests {
+		stats := &models.FunctionStats{
+			Name:         "f",
+			References:   tt.references,
+			LastModified: tt.edited,
+			TotalChanges: tt.changes,
+		}
+		if got := hc.isLikelyDeadFunctionCode(stats, now, policy); got != tt.want {
+			t.Errorf("%s: dead = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// The weights come from the rules like every other signal
+	cfg := &models.RulesConfig{DeadCode: models.DeadCodeRules{Rules: []models.DeadCodeRule{{
+		Languages:        []string{"javascript"},
+		DeadCodeSettings: models.DeadCodeSettings{Weights: map[models.DeadCodeReason]float64{models.DeadCodeReasonUnreferenced: 0}},
+	}}}}
+	rules, err := NewRuleEngine(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &models.FunctionStats{Name: "f", References: &none, LastModified: now.AddDate(-1, 0, 0), TotalChanges: 5}
+	if hc.isLikelyDeadFunctionCode(stats, now, rules.Policy("src/app.js", "javascript", functionNode)) {
+		t.Errorf("unreferenced weighted 0: dead, want alive")
+	}
+}
+
+func TestCalculateChangeFrequency(t *testing.T) {
+	hc := NewHeatCalculator()
+
+	tests := []struct {
+		name         string
+		total        int
+		changesByDay map[int]int
+		want         float64
+	}{
+		{"no changes", 0, nil, 0},
+		// a fresh commit spans a day, floored to a week
+		{"one fresh commit", 1, map[int]int{0: 1}, 1},
+		{"six days", 3, map[int]int{0: 1, 5: 2}, 3},
+		// oldest change 6 days ago spans exactly a week
+		{"seven days", 7, map[int]int{6: 7}, 7},
+		{"two weeks", 4, map[int]int{0: 2, 13: 2}, 2},
+		{"ten weeks", 5, map[int]int{69: 5}, 0.5},
+	}
+
+	for _, tt := range tests {
+		if got := hc.calculateChangeFrequency(tt.total, tt.changesByDay); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: frequency = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitChanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		changesByDay  map[int]int
+		windowDays    int
+		recent, older int
+	}{
+		{"empty", nil, 180, 0, 0},
+		// day 90 is the first of the older half
+		{"at the midpoint", map[int]int{0: 1, 89: 2, 90: 4, 179: 8}, 180, 3, 12},
+		{"odd window", map[int]int{14: 1, 15: 1}, 31, 1, 1},
+		// no window falls back to halves of 90 days
+		{"no window", map[int]int{89: 1, 90: 1}, 0, 1, 1},
+		{"one-day window", map[int]int{0: 1, 100: 1}, 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		recent, older := splitChanges(tt.changesByDay, tt.windowDays)
+		if recent != tt.recent || older != tt.older {
+			t.Errorf("%s: split = %d, %d, want %d, %d", tt.name, recent, older, tt.recent, tt.older)
+		}
+	}
+}
+
+func TestClassifyTrend(t *testing.T) {
+	tests := []struct {
+		recent, older int
+		want          models.Trend
+	}{
+		{0, 0, models.TrendSteady},
+		// one commit is below trendMinDelta
+		{1, 0, models.TrendSteady},
+		{2, 0, models.TrendRising},
+		{0, 2, models.TrendCooling},
+		// exactly trendRatio with the minimum delta
+		{6, 4, models.TrendRising},
+		{4, 6, models.TrendCooling},
+		// enough delta, not enough ratio
+		{7, 5, models.TrendSteady},
+		{5, 7, models.TrendSteady},
+		// enough ratio, not enough delta
+		{3, 2, models.TrendSteady},
+		{2, 3, models.TrendSteady},
+		{30, 10, models.TrendRising},
+	}
+
+	for _, tt := range tests {
+		if got := classifyTrend(tt.recent, tt.older); got != tt.want {
+			t.Errorf("classifyTrend(%d, %d) = %s, want %s", tt.recent, tt.older, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: What Makes Code "Hot"?
+//
+// Every model answers one question: how much is a change N days old worth?
+//
+//   exponential  0.5^(days / halfLife)         recent work dominates, old work fades
+//   linear       max(0, 1 - days / window)     everything in the window counts, less with age
+//   step         weight of the first bucket    "last month 1, last quarter 0.5, ..."
+//                with days <= maxDays
+//   count        1                             plain commit count, no recency at all
+//
+// raw = Σ amount(day) · weight(days), times (1 + author bonus) for files,
+// where a day's amount is its commit count, or with the churn weighting
+// its lines added + removed (log2(1 + lines) with log dampening, so a
+// 5000-line vendored drop counts ~12, not 5000x a typo fix).
+// The default, exponential with DecayRate 0.01 (e^-0.01·days), is the same
+// curve as a half-life of ln 2 / 0.01 ≈ 69 days.
+
+// HeatModel weighs a change by its age
+type HeatModel interface {
+	// Weight of one change made daysAgo days ago
+	Weight(daysAgo int) float64
+
+	// AuthorBonus for a file with that many distinct authors
+	AuthorBonus(authors int) float64
+
+	// Amount one day of work counts for, from its commits and churned lines
+	Amount(commits, lines int) float64
+
+	// Normalize maps raw scores to the configured scale, ignored (skip) ones to 0
+	Normalize(raw []float64, skip []bool) []float64
+
+	// Config is the resolved configuration, echoed in responses
+	Config() *models.HeatModelConfig
+}
+
+// Defaults for parameters a request leaves unset
+const (
+	defaultAuthorBonusCap = 0.5
+	defaultWindowDays     = 180
+)
+
+var defaultHeatBuckets = []models.HeatBucket{
+	{MaxDays: 30, Weight: 1},
+	{MaxDays: 90, Weight: 0.5},
+	{MaxDays: 180, Weight: 0.25},
+	{MaxDays: 365, Weight: 0.1},
+}
+
+// NewHeatModel resolves a heat model config. A nil config gives the default
+// exponential decay.
+func NewHeatModel(cfg *models.HeatModelConfig) (HeatModel, error) {
+	resolved := models.HeatModelConfig{Model: models.HeatModelExponential}
+	if cfg != nil {
+		resolved = *cfg
+	}
+	if resolved.Model == "" {
+		resolved.Model = models.HeatModelExponential
+	}
+
+	authorBonusCap := defaultAuthorBonusCap
+	if resolved.AuthorBonusCap != nil {
+		authorBonusCap = *resolved.AuthorBonusCap
+	}
+	if authorBonusCap < 0 {
+		return nil, fmt.Errorf("authorBonusCap must not be negative, got %v", authorBonusCap)
+	}
+	resolved.AuthorBonusCap = &authorBonusCap
+
+	switch resolved.Weighting {
+	case "":
+		resolved.Weighting = models.HeatWeightingCommits
+	case models.HeatWeightingCommits, models.HeatWeightingChurn:
+	default:
+		return nil, fmt.Errorf("unknown weighting %q (want %s or %s)", resolved.Weighting,
+			models.HeatWeightingCommits, models.HeatWeightingChurn)
+	}
+	if resolved.Weighting != models.HeatWeightingChurn {
+		resolved.LogDampening = false
+	}
+
+	switch resolved.Normalization {
+	case "":
+		resolved.Normalization = models.NormalizationMax
+	case models.NormalizationMax, models.NormalizationPercentile, models.NormalizationLog,
+		models.NormalizationZScore, models.NormalizationAbsolute:
+	default:
+		return nil, fmt.Errorf("unknown normalization %q (want %s, %s, %s, %s or %s)", resolved.Normalization,
+			models.NormalizationMax, models.NormalizationPercentile, models.NormalizationLog,
+			models.NormalizationZScore, models.NormalizationAbsolute)
+	}
+
+	base := heatModelBase{
+		authorBonusCap: authorBonusCap,
+		churn:          resolved.Weighting == models.HeatWeightingChurn,
+		logDampening:   resolved.LogDampening,
+		normalization:  resolved.Normalization,
+	}
+
+	switch resolved.Model {
+	case models.HeatModelExponential:
+		if resolved.HalfLifeDays == 0 {
+			resolved.HalfLifeDays = math.Ln2 / DecayRate
+		}
+		if resolved.HalfLifeDays < 0 {
+			return nil, fmt.Errorf("halfLifeDays must be positive, got %v", resolved.HalfLifeDays)
+		}
+		resolved.WindowDays, resolved.Buckets = 0, nil
+		return &exponentialModel{heatModelBase: base, config: resolved}, nil
+
+	case models.HeatModelLinear:
+		if resolved.WindowDays == 0 {
+			resolved.WindowDays = defaultWindowDays
+		}
+		if resolved.WindowDays < 0 {
+			return nil, fmt.Errorf("windowDays must be positive, got %d", resolved.WindowDays)
+		}
+		resolved.HalfLifeDays, resolved.Buckets = 0, nil
+		return &linearModel{heatModelBase: base, config: resolved}, nil
+
+	case models.HeatModelStep:
+		if len(resolved.Buckets) == 0 {
+			resolved.Buckets = defaultHeatBuckets
+		}
+		buckets := make([]models.HeatBucket, len(resolved.Buckets))
+		copy(buckets, resolved.Buckets)
+		sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].MaxDays < buckets[j].MaxDays })
+		for _, bucket := range buckets {
+			if bucket.MaxDays < 0 || bucket.Weight < 0 {
+				return nil, fmt.Errorf("buckets need non-negative maxDays and weight, got %+v", bucket)
+			}
+		}
+		resolved.Buckets = buckets
+		resolved.HalfLifeDays, resolved.WindowDays = 0, 0
+		return &stepModel{heatModelBase: base, config: resolved}, nil
+
+	case models.HeatModelCount:
+		resolved.HalfLifeDays, resolved.WindowDays, resolved.Buckets = 0, 0, nil
+		return &countModel{heatModelBase: base, config: resolved}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown heat model %q (want %s, %s, %s or %s)", resolved.Model,
+			models.HeatModelExponential, models.HeatModelLinear, models.HeatModelStep, models.HeatModelCount)
+	}
+}
+
+// heatModelBase holds what every model shares
+type heatModelBase struct {
+	authorBonusCap float64
+	churn          bool
+	logDampening   bool
+	normalization  string
+}
+
+// AuthorBonus: +0.1 per distinct author, up to the cap
+func (b heatModelBase) AuthorBonus(authors int) float64 {
+	return math.Min(float64(authors)*0.1, b.authorBonusCap)
+}
+
+// Amount: commits, or churned lines with the churn weighting
+func (b heatModelBase) Amount(commits, lines int) float64 {
+	if !b.churn {
+		return float64(commits)
+	}
+	if b.logDampening {
+		return math.Log2(1 + float64(lines))
+	}
+	return float64(lines)
+}
+
+type exponentialModel struct {
+	heatModelBase
+	config models.HeatModelConfig
+}
+
+func (m *exponentialModel) Weight(daysAgo int) float64 {
+	return math.Pow(0.5, float64(daysAgo)/m.config.HalfLifeDays)
+}
+
+func (m *exponentialModel) Config() *models.HeatModelConfig {
+	cfg := m.config
+	return &cfg
+}
+
+type linearModel struct {
+	heatModelBase
+	config models.HeatModelConfig
+}
+
+func (m *linearModel) Weight(daysAgo int) float64 {
+	return math.Max(0, 1-float64(daysAgo)/float64(m.config.WindowDays))
+}
+
+func (m *linearModel) Config() *models.HeatModelConfig {
+	cfg := m.config
+	return &cfg
+}
+
+type stepModel struct {
+	heatModelBase
+	config models.HeatModelConfig
+}
+
+// Weight of the first bucket old enough; changes older than every bucket count 0
+func (m *stepModel) Weight(daysAgo int) float64 {
+	for _, bucket := range m.config.Buckets {
+		if daysAgo <= bucket.MaxDays {
+			return bucket.Weight
+		}
+	}
+	return 0
+}
+
+func (m *stepModel) Config() *models.HeatModelConfig {
+	cfg := m.config
+	cfg.Buckets = append([]models.HeatBucket(nil), m.config.Buckets...)
+	return &cfg
+}
+
+type countModel struct {
+	heatModelBase
+	config models.HeatModelConfig
+}
+
+func (m *countModel) Weight(daysAgo int) float64 {
+	return 1
+}
+
+func (m *countModel) Config() *models.HeatModelConfig {
+	cfg := m.config
+	return &cfg
+}
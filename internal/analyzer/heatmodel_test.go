@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func TestHeatModelWeight(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *models.HeatModelConfig
+		want map[int]float64 // days ago -> weight
+	}{
+		{"default exponential", nil, map[int]float64{0: 1, 69: math.Exp(-0.01 * 69)}},
+		{"exponential half-life", &models.HeatModelConfig{Model: models.HeatModelExponential, HalfLifeDays: 30},
+			map[int]float64{0: 1, 30: 0.5, 60: 0.25}},
+		{"linear", &models.HeatModelConfig{Model: models.HeatModelLinear, WindowDays: 100},
+			map[int]float64{0: 1, 50: 0.5, 100: 0, 150: 0}},
+		{"linear default window", &models.HeatModelConfig{Model: models.HeatModelLinear},
+			map[int]float64{90: 0.5, 180: 0}},
+		{"step default buckets", &models.HeatModelConfig{Model: models.HeatModelStep},
+			map[int]float64{0: 1, 30: 1, 31: 0.5, 90: 0.5, 91: 0.25, 180: 0.25, 365: 0.1, 366: 0}},
+		{"step buckets are sorted", &models.HeatModelConfig{Model: models.HeatModelStep,
+			Buckets: []models.HeatBucket{{MaxDays: 60, Weight: 0.2}, {MaxDays: 7, Weight: 1}}},
+			map[int]float64{7: 1, 8: 0.2, 61: 0}},
+		{"count", &models.HeatModelConfig{Model: models.HeatModelCount},
+			map[int]float64{0: 1, 1000: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := NewHeatModel(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for days, want := range tt.want {
+				if got := model.Weight(days); math.Abs(got-want) > 1e-9 {
+					t.Errorf("Weight(%d) = %v, want %v", days, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeatModelAmount(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            *models.HeatModelConfig
+		commits, lines int
+		want           float64
+	}{
+		{"commits", nil, 3, 500, 3},
+		{"churn", &models.HeatModelConfig{Weighting: models.HeatWeightingChurn}, 3, 500, 500},
+		{"churn, dampened", &models.HeatModelConfig{Weighting: models.HeatWeightingChurn, LogDampening: true}, 3, 7, 3},
+		{"dampened vendored drop", &models.HeatModelConfig{Weighting: models.HeatWeightingChurn, LogDampening: true}, 1, 4095, 12},
+		{"dampening needs churn", &models.HeatModelConfig{LogDampening: true}, 3, 7, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := NewHeatModel(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := model.Amount(tt.commits, tt.lines); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Amount(%d, %d) = %v, want %v", tt.commits, tt.lines, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeatModelAuthorBonus(t *testing.T) {
+	model, _ := NewHeatModel(nil)
+	for authors, want := range map[int]float64{0: 0, 3: 0.3, 5: 0.5, 12: 0.5} {
+		if got := model.AuthorBonus(authors); math.Abs(got-want) > 1e-9 {
+			t.Errorf("AuthorBonus(%d) = %v, want %v", authors, got, want)
+		}
+	}
+
+	off := 0.0
+	model, _ = NewHeatModel(&models.HeatModelConfig{AuthorBonusCap: &off})
+	if got := model.AuthorBonus(4); got != 0 {
+		t.Errorf("AuthorBonus with a zero cap = %v, want 0", got)
+	}
+}
+
+func TestHeatModelConfig(t *testing.T) {
+	model, _ := NewHeatModel(nil)
+	cfg := model.Config()
+	if cfg.Model != models.HeatModelExponential || math.Abs(cfg.HalfLifeDays-math.Ln2/DecayRate) > 1e-9 ||
+		cfg.Weighting != models.HeatWeightingCommits || cfg.Normalization != models.NormalizationMax ||
+		cfg.AuthorBonusCap == nil || *cfg.AuthorBonusCap != defaultAuthorBonusCap {
+		t.Errorf("default config = %+v", cfg)
+	}
+
+	// Parameters of other models are dropped
+	model, _ = NewHeatModel(&models.HeatModelConfig{Model: models.HeatModelLinear, HalfLifeDays: 10,
+		Buckets: defaultHeatBuckets})
+	if cfg := model.Config(); cfg.HalfLifeDays != 0 || cfg.Buckets != nil || cfg.WindowDays != defaultWindowDays {
+		t.Errorf("linear config = %+v", cfg)
+	}
+}
+
+func TestNewHeatModelErrors(t *testing.T) {
+	negative := -0.1
+	tests := []struct {
+		name string
+		cfg  models.HeatModelConfig
+		want string
+	}{
+		{"unknown model", models.HeatModelConfig{Model: "gaussian"}, `unknown heat model "gaussian"`},
+		{"negative half-life", models.HeatModelConfig{HalfLifeDays: -1}, "halfLifeDays"},
+		{"negative window", models.HeatModelConfig{Model: models.HeatModelLinear, WindowDays: -1}, "windowDays"},
+		{"negative bucket", models.HeatModelConfig{Model: models.HeatModelStep,
+			Buckets: []models.HeatBucket{{MaxDays: 30, Weight: -1}}}, "buckets"},
+		{"negative author cap", models.HeatModelConfig{AuthorBonusCap: &negative}, "authorBonusCap"},
+		{"unknown weighting", models.HeatModelConfig{Weighting: "lines"}, `unknown weighting "lines"`},
+		{"unknown normalization", models.HeatModelConfig{Normalization: "rank"}, `unknown normalization "rank"`},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewHeatModel(&tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
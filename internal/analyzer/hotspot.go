@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+// LEARNING MOMENT: Hotspots (Adam Tornhill, "Your Code as a Crime Scene")
+//
+// Heat alone ranks a busy config file next to a 2000-line god class.
+// Complexity alone ranks code nobody touches anymore. The risk is where
+// both meet: complicated code that keeps changing.
+//
+//   hotspot raw = raw heat × cyclomatic complexity
+//
+// (for a file the complexity is the sum over its units). The raw value is
+// normalized like heat (same mode), and files are ranked by it: rank 1 is
+// the first place to look for refactoring. Files without parsed units or
+// without changes in the window have no rank.
+
+// ValidateSortBy checks a requested tree order
+func ValidateSortBy(sortBy string) error {
+	switch sortBy {
+	case "", models.SortByHeat, models.SortByHotspot:
+		return nil
+	default:
+		return fmt.Errorf("unknown sort %q (want %s or %s)", sortBy, models.SortByHeat, models.SortByHotspot)
+	}
+}
+
+func toComplexity(c *parser.Complexity) *models.Complexity {
+	if c == nil {
+		return nil
+	}
+	return &models.Complexity{
+		Cyclomatic: c.Cyclomatic,
+		MaxNesting: c.MaxNesting,
+		Lines:      c.Lines,
+	}
+}
+
+// rollUpComplexity sums a file's units. Notebook cells already contain the
+// functions defined in them, so only cells count there.
+func rollUpComplexity(functions []*models.FunctionStats) *models.Complexity {
+	hasCells := false
+	for _, fn := range functions {
+		if fn.Type == string(parser.TypeCell) {
+			hasCells = true
+			break
+		}
+	}
+
+	var total *models.Complexity
+	for _, fn := range functions {
+		if fn.Complexity == nil || (hasCells && fn.Type != string(parser.TypeCell)) {
+			continue
+		}
+		if total == nil {
+			total = &models.Complexity{}
+		}
+
+		total.Cyclomatic += fn.Complexity.Cyclomatic
+		total.Lines += fn.Complexity.Lines
+		total.MaxCyclomatic = max(total.MaxCyclomatic, fn.Complexity.Cyclomatic)
+		total.MaxNesting = max(total.MaxNesting, fn.Complexity.MaxNesting)
+	}
+
+	return total
+}
+
+// hotspotScores combines raw heat with complexity; nodes without a
+// complexity (or ignored) score 0 and stay off the scale
+func hotspotScores(rawHeat []float64, complexities []*models.Complexity, ignored []bool, model HeatModel) []float64 {
+	raw := make([]float64, len(rawHeat))
+	skip := make([]bool, len(rawHeat))
+
+	for i := range rawHeat {
+		if complexities[i] == nil || ignored[i] {
+			skip[i] = true
+			continue
+		}
+		raw[i] = rawHeat[i] * float64(complexities[i].Cyclomatic)
+	}
+
+	return model.Normalize(raw, skip)
+}
+
+// rankHotspots scores every file node and numbers them, riskiest first
+func rankHotspots(files []*models.FileNode, model HeatModel) {
+	rawHeat := make([]float64, len(files))
+	complexities := make([]*models.Complexity, len(files))
+	ignored := make([]bool, len(files))
+
+	for i, node := range files {
+		rawHeat[i] = node.HeatScore.RawScore
+		complexities[i] = node.Complexity
+		// Stable code is no hotspot, however complex
+		ignored[i] = node.Suppression.Ignored() || node.HeatScore.NoChangesInWindow
+	}
+
+	scores := hotspotScores(rawHeat, complexities, ignored, model)
+
+	ranked := []*models.FileNode{}
+	for i, node := range files {
+		node.HotspotScore = scores[i]
+		if complexities[i] != nil && !ignored[i] {
+			ranked = append(ranked, node)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].HotspotScore != ranked[j].HotspotScore {
+			return ranked[i].HotspotScore > ranked[j].HotspotScore
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+	for i, node := range ranked {
+		node.HotspotRank = i + 1
+	}
+}
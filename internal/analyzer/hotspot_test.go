@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+func unit(name string, unitType parser.FunctionType, cyclomatic, nesting, lines int) *models.FunctionStats {
+	return &models.FunctionStats{
+		Name:       name,
+		Type:       string(unitType),
+		Complexity: &models.Complexity{Cyclomatic: cyclomatic, MaxNesting: nesting, Lines: lines},
+	}
+}
+
+func TestRollUpComplexity(t *testing.T) {
+	structUnit := &models.FunctionStats{Name: "Config", Type: string(parser.TypeStruct)}
+
+	tests := []struct {
+		name      string
+		functions []*models.FunctionStats
+		want      *models.Complexity
+	}{
+		{
+			name:      "no units",
+			functions: nil,
+			want:      nil,
+		},
+		{
+			name:      "only types",
+			functions: []*models.FunctionStats{structUnit},
+			want:      nil,
+		},
+		{
+			name: "functions and closures add up",
+			functions: []*models.FunctionStats{
+				unit("run", parser.TypeFunction, 4, 2, 20),
+				unit("run.func1", parser.TypeClosure, 2, 1, 5),
+				structUnit,
+				unit("(*Cache).Get", parser.TypeMethod, 3, 1, 10),
+			},
+			want: &models.Complexity{Cyclomatic: 9, MaxNesting: 2, Lines: 35, MaxCyclomatic: 4},
+		},
+		{
+			name: "notebook counts cells, not the functions inside them",
+			functions: []*models.FunctionStats{
+				unit("cell[1]", parser.TypeCell, 2, 1, 3),
+				unit("cell[2]", parser.TypeCell, 5, 2, 12),
+				unit("train", parser.TypeFunction, 4, 3, 8),
+			},
+			want: &models.Complexity{Cyclomatic: 7, MaxNesting: 2, Lines: 15, MaxCyclomatic: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rollUpComplexity(tt.functions)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("rollUpComplexity = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A parsed notebook must count a function's decisions once, through its cell
+func TestRollUpComplexityNotebook(t *testing.T) {
+	notebook := `{
+ "cells": [
+  {"cell_type": "code", "source": ["def f(x):\n", "    if x and x[0]:\n", "        return 1\n"]},
+  {"cell_type": "code", "source": ["for y in range(3):\n", "    f([y])\n"]}
+ ],
+ "metadata": {"language_info": {"name": "python"}}
+}`
+
+	units, err := parser.NewNotebookParser().Parse(strings.NewReader(notebook))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	functions := []*models.FunctionStats{}
+	cells := 0
+	for _, u := range units {
+		if u.Type == parser.TypeCell {
+			cells += u.Complexity.Cyclomatic
+		}
+		functions = append(functions, &models.FunctionStats{
+			Name:       u.Name,
+			Type:       string(u.Type),
+			Complexity: toComplexity(u.Complexity),
+		})
+	}
+
+	total := rollUpComplexity(functions)
+	if total == nil {
+		t.Fatal("rollUpComplexity = nil")
+	}
+	// cell 1: 1 + if, and; cell 2: 1 + for
+	if cells != 5 || total.Cyclomatic != cells {
+		t.Errorf("cyclomatic = %d (cells %d), want 5", total.Cyclomatic, cells)
+	}
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/pkg"
+)
+
+// IgnoreFileName is looked up at the root of the analyzed repository
+const IgnoreFileName = ".firesightignore"
+
+// IgnoreFile is a parsed .firesightignore: gitignore syntax, the last
+// matching line wins and "!pattern" re-includes
+type IgnoreFile struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	glob   *pkg.Glob
+	negate bool
+}
+
+// LoadIgnoreFile reads .firesightignore. Returns nil (and no error) when the repo has none.
+func LoadIgnoreFile(repoPath string) (*IgnoreFile, error) {
+	file, err := os.Open(filepath.Join(repoPath, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ignore := &IgnoreFile{}
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		// \# and \! escape a leading special character
+		line = strings.TrimPrefix(line, `\`)
+
+		glob, err := pkg.CompileGlob(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", IgnoreFileName, lineNum, line, err)
+		}
+		rule.glob = glob
+		ignore.rules = append(ignore.rules, rule)
+	}
+
+	return ignore, scanner.Err()
+}
+
+// Match returns the pattern that ignores path, if any
+func (ig *IgnoreFile) Match(path string) (string, bool) {
+	if ig == nil {
+		return "", false
+	}
+
+	pattern, ignored := "", false
+	for _, rule := range ig.rules {
+		if rule.glob.MatchOrParent(path) {
+			pattern, ignored = rule.glob.Pattern, !rule.negate
+		}
+	}
+
+	if !ignored {
+		return "", false
+	}
+	return pattern, true
+}
@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeIgnoreFile(t *testing.T, content string) *IgnoreFile {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := LoadIgnoreFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ignore
+}
+
+func TestIgnoreFileMatch(t *testing.T) {
+	ignore := writeIgnoreFile(t, `# generated code
+gen/
+*.pb.go
+!keep.pb.go
+
+vendor/**
+!vendor/ours/
+vendor/ours/tmp/
+
+\#scratch.go
+\!important.go
+docs/*.md
+`)
+
+	tests := []struct {
+		path        string
+		wantPattern string
+		wantIgnored bool
+	}{
+		{"gen/models.go", "gen/", true},
+		{"internal/gen/deep/models.go", "gen/", true},
+		{"api/user.pb.go", "*.pb.go", true},
+		{"api/keep.pb.go", "", false},
+		{"vendor/lib/x.go", "vendor/**", true},
+		{"vendor/ours/x.go", "", false},
+		{"vendor/ours/tmp/x.go", "vendor/ours/tmp/", true},
+		{"#scratch.go", `#scratch.go`, true},
+		{"!important.go", `!important.go`, true},
+		{"important.go", "", false},
+		{"docs/guide.md", "docs/*.md", true},
+		{"docs/api/guide.md", "", false},
+		{"main.go", "", false},
+	}
+
+	for _, tt := range tests {
+		pattern, ignored := ignore.Match(tt.path)
+		if pattern != tt.wantPattern || ignored != tt.wantIgnored {
+			t.Errorf("Match(%q) = %q, %v, want %q, %v", tt.path, pattern, ignored, tt.wantPattern, tt.wantIgnored)
+		}
+	}
+}
+
+func TestIgnoreFileLastMatchWins(t *testing.T) {
+	ignore := writeIgnoreFile(t, "!*.go\n*.go\n")
+	if _, ignored := ignore.Match("main.go"); !ignored {
+		t.Errorf("a later pattern should override an earlier negation")
+	}
+
+	ignore = writeIgnoreFile(t, "*.go\n!main.go\n*_gen.go\n")
+	for path, want := range map[string]bool{"main.go": false, "util.go": true, "main_gen.go": true} {
+		if _, ignored := ignore.Match(path); ignored != want {
+			t.Errorf("Match(%q) ignored = %v, want %v", path, ignored, want)
+		}
+	}
+}
+
+func TestLoadIgnoreFile(t *testing.T) {
+	if ignore, err := LoadIgnoreFile(t.TempDir()); ignore != nil || err != nil {
+		t.Errorf("no file: got %v, %v, want nil, nil", ignore, err)
+	}
+
+	var missing *IgnoreFile
+	if _, ignored := missing.Match("main.go"); ignored {
+		t.Errorf("a nil ignore file ignored something")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte("ok/\n[z-a]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadIgnoreFile(dir); err == nil || !strings.Contains(err.Error(), IgnoreFileName+":2") {
+		t.Errorf("err = %v, want the bad line reported", err)
+	}
+}
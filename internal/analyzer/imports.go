@@ -0,0 +1,494 @@
+package analyzer
+
+import (
+	"encoding/json"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+// LEARNING MOMENT: Orphans in the Import Graph
+//
+// A cold function might still be called every day; a file nobody imports
+// can't be. We build a file-level graph (A -> B when A imports B):
+//
+//   Go      a file imports whole packages: A -> every file of the package.
+//           Files of one package are compiled together, so they are linked too.
+//   JS/TS   import / require / import() of relative paths, trying the usual
+//           extensions and index files: './ui' -> ui.ts, ui/index.tsx, ...
+//   Python  import a.b / from a.b import c / from . import x, matched to
+//           files by module path suffix (so src/ layouts work); importing
+//           a.b.c also runs a/__init__.py and a/b/__init__.py
+//
+// Then a BFS from the entrypoint files: package main, tests, index/main
+// files and package.json entries, scripts with a __main__ block, framework
+// conventions (pages/, routes/, urls.py, ...), public Go packages (library API),
+// and any file with a function classified as an entrypoint.
+// Whatever the BFS doesn't reach is an orphan.
+//
+// Unresolvable imports (aliases, bare packages, dynamic paths) are simply
+// not edges, so the usual mistake is an orphan that isn't one - which is
+// why an orphan is one more dead-code signal, not a verdict of its own.
+
+var (
+	pyImportModules = regexp.MustCompile(`^\s*import\s+([\w., ]+)`)
+	pyFromImport    = regexp.MustCompile(`^\s*from\s+(\.*[\w.]*)\s+import\s+\(?([\w, ]*)`)
+
+	jsExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".vue", ".svelte", ".astro", ".d.ts"}
+
+	// Entry stems for JS/TS at the top of a project
+	jsEntryStems = map[string]bool{"index": true, "main": true, "app": true, "server": true, "cli": true}
+	jsEntryDirs  = map[string]bool{".": true, "src": true, "bin": true, "lib": true}
+
+	// Files frameworks load by convention (Django, Celery, pytest, WSGI/ASGI)
+	pyEntryFiles = map[string]bool{
+		"manage.py": true, "setup.py": true, "conftest.py": true, "wsgi.py": true, "asgi.py": true,
+		"settings.py": true, "urls.py": true, "admin.py": true, "apps.py": true, "models.py": true,
+		"tasks.py": true, "__main__.py": true,
+	}
+)
+
+// ImportGraph is a file-level import graph of Go, JS/TS and Python code
+type ImportGraph struct {
+	files   map[string]bool            // every file taking part
+	edges   map[string]map[string]bool // importer -> imported
+	entries map[string]bool
+}
+
+// BuildImportGraph reads every source file among paths and links imports
+func BuildImportGraph(repoPath string, paths []string, detector *parser.LanguageDetector) *ImportGraph {
+	g := &ImportGraph{
+		files:   make(map[string]bool),
+		edges:   make(map[string]map[string]bool),
+		entries: make(map[string]bool),
+	}
+
+	pathSet := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		pathSet[p] = true
+	}
+
+	contents := make(map[string]string)
+	langs := make(map[string]parser.Language)
+	for _, p := range paths {
+		content, err := os.ReadFile(filepath.Join(repoPath, p))
+		if err != nil {
+			continue
+		}
+
+		lang := detector.Detect(p, content).Language
+		switch lang {
+		case parser.LangGo, parser.LangJavaScript, parser.LangTypeScript,
+			parser.LangVue, parser.LangSvelte, parser.LangAstro, parser.LangPython:
+		default:
+			continue
+		}
+
+		g.files[p] = true
+		contents[p] = string(content)
+		langs[p] = lang
+	}
+
+	g.linkGo(repoPath, paths, contents, langs)
+	pyModules := indexPythonModules(langs)
+	for p, content := range contents {
+		switch langs[p] {
+		case parser.LangGo:
+		case parser.LangPython:
+			g.linkPython(p, content, pyModules, pathSet)
+		default:
+			g.linkJS(p, content, pathSet)
+		}
+	}
+
+	packageEntries := readPackageJSONEntries(repoPath, paths)
+	for p, content := range contents {
+		if packageEntries[p] || isEntryFile(p, langs[p], content, pathSet) {
+			g.entries[p] = true
+		}
+	}
+
+	return g
+}
+
+func (g *ImportGraph) addEdge(from, to string) {
+	if from == to || !g.files[to] {
+		return
+	}
+	if g.edges[from] == nil {
+		g.edges[from] = make(map[string]bool)
+	}
+	g.edges[from][to] = true
+}
+
+// linkGo: files import packages (directories), package files know each other
+func (g *ImportGraph) linkGo(repoPath string, paths []string, contents map[string]string, langs map[string]parser.Language) {
+	modules := make(map[string]string)
+	for _, p := range paths {
+		if path.Base(p) == "go.mod" {
+			if modulePath := readModulePath(filepath.Join(repoPath, p)); modulePath != "" {
+				modules[path.Dir(p)] = modulePath
+			}
+		}
+	}
+
+	dirFiles := make(map[string][]string) // package dir -> non-test files
+	dirByImport := make(map[string]string)
+	for p := range contents {
+		if langs[p] != parser.LangGo {
+			continue
+		}
+		dir := path.Dir(p)
+		if !strings.HasSuffix(p, "_test.go") {
+			dirFiles[dir] = append(dirFiles[dir], p)
+		}
+		dirByImport[resolveImportPath(dir, modules)] = dir
+	}
+
+	fset := token.NewFileSet()
+	for p, content := range contents {
+		if langs[p] != parser.LangGo {
+			continue
+		}
+
+		// Same package: compiled together, one reached means all reached
+		for _, sibling := range dirFiles[path.Dir(p)] {
+			g.addEdge(p, sibling)
+		}
+
+		file, err := goparser.ParseFile(fset, p, content, goparser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			if dir, ok := dirByImport[importPath]; ok {
+				for _, target := range dirFiles[dir] {
+					g.addEdge(p, target)
+				}
+			}
+		}
+	}
+}
+
+// linkJS resolves relative import specifiers ("./x", "../y", "@/z")
+func (g *ImportGraph) linkJS(p, content string, pathSet map[string]bool) {
+	for _, line := range strings.Split(content, "\n") {
+		for _, matches := range jsModuleSource.FindAllStringSubmatch(line, -1) {
+			if target, ok := resolveJSImport(path.Dir(p), matches[1], pathSet); ok {
+				g.addEdge(p, target)
+			}
+		}
+	}
+}
+
+func resolveJSImport(dir, specifier string, pathSet map[string]bool) (string, bool) {
+	var bases []string
+	switch {
+	case strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") || specifier == "." || specifier == "..":
+		bases = []string{path.Join(dir, specifier)}
+	case strings.HasPrefix(specifier, "@/") || strings.HasPrefix(specifier, "~/"):
+		// Common bundler aliases for the source root
+		bases = []string{path.Join("src", specifier[2:]), path.Clean(specifier[2:])}
+	default:
+		return "", false // a package from node_modules
+	}
+
+	for _, base := range bases {
+		candidates := []string{base}
+		// TypeScript ESM imports './x.js' for x.ts
+		if ext := path.Ext(base); ext == ".js" || ext == ".jsx" || ext == ".mjs" {
+			stem := strings.TrimSuffix(base, ext)
+			candidates = append(candidates, stem+".ts", stem+".tsx", stem+".mts")
+		}
+		for _, ext := range jsExtensions {
+			candidates = append(candidates, base+ext)
+		}
+		for _, ext := range jsExtensions {
+			candidates = append(candidates, path.Join(base, "index"+ext))
+		}
+
+		for _, candidate := range candidates {
+			if pathSet[candidate] {
+				return candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// indexPythonModules maps every dotted suffix of a module path to its files:
+// src/app/models.py -> "src.app.models", "app.models", "models"
+func indexPythonModules(langs map[string]parser.Language) map[string][]string {
+	index := make(map[string][]string)
+
+	for p, lang := range langs {
+		if lang != parser.LangPython {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimSuffix(p, ".py"), "/")
+		if parts[len(parts)-1] == "__init__" {
+			parts = parts[:len(parts)-1]
+		}
+		for i := range parts {
+			module := strings.Join(parts[i:], ".")
+			index[module] = append(index[module], p)
+		}
+	}
+
+	return index
+}
+
+func (g *ImportGraph) linkPython(p, content string, modules map[string][]string, pathSet map[string]bool) {
+	link := func(module string) bool {
+		targets := modules[module]
+		for _, target := range targets {
+			g.addEdge(p, target)
+			g.linkPackageInits(p, target, pathSet)
+		}
+		return len(targets) > 0
+	}
+	linkRelative := func(dir string) bool {
+		for _, candidate := range []string{dir + ".py", path.Join(dir, "__init__.py")} {
+			if pathSet[candidate] {
+				g.addEdge(p, candidate)
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = stripPythonComment(line)
+
+		if matches := pyImportModules.FindStringSubmatch(line); matches != nil {
+			for _, item := range strings.Split(matches[1], ",") {
+				// import a.b as c
+				if fields := strings.Fields(item); len(fields) > 0 {
+					link(fields[0])
+				}
+			}
+			continue
+		}
+
+		matches := pyFromImport.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		module, names := matches[1], strings.Split(matches[2], ",")
+
+		if strings.HasPrefix(module, ".") {
+			// from ..pkg.mod import x: one dot is this package, each more goes up
+			dots := len(module) - len(strings.TrimLeft(module, "."))
+			dir := path.Dir(p)
+			for i := 1; i < dots; i++ {
+				dir = path.Dir(dir)
+			}
+			if rest := strings.TrimLeft(module, "."); rest != "" {
+				dir = path.Join(dir, strings.ReplaceAll(rest, ".", "/"))
+			}
+
+			linkRelative(dir)
+			for _, name := range names {
+				if fields := strings.Fields(name); len(fields) > 0 {
+					linkRelative(path.Join(dir, fields[0])) // a submodule, or just a name
+				}
+			}
+			continue
+		}
+
+		link(module)
+		for _, name := range names {
+			if fields := strings.Fields(name); len(fields) > 0 {
+				link(module + "." + fields[0])
+			}
+		}
+	}
+}
+
+// linkPackageInits: importing a/b/c.py also runs a/__init__.py and a/b/__init__.py
+func (g *ImportGraph) linkPackageInits(from, target string, pathSet map[string]bool) {
+	for dir := path.Dir(target); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		init := path.Join(dir, "__init__.py")
+		if !pathSet[init] {
+			return
+		}
+		g.addEdge(from, init)
+	}
+}
+
+// readPackageJSONEntries collects main/module/browser/bin/exports targets
+func readPackageJSONEntries(repoPath string, paths []string) map[string]bool {
+	entries := make(map[string]bool)
+
+	for _, p := range paths {
+		if path.Base(p) != "package.json" || strings.Contains(p, "node_modules/") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(repoPath, p))
+		if err != nil {
+			continue
+		}
+
+		var manifest map[string]any
+		if json.Unmarshal(content, &manifest) != nil {
+			continue
+		}
+
+		dir := path.Dir(p)
+		for _, field := range []string{"main", "module", "browser", "bin", "exports"} {
+			for _, target := range collectJSONStrings(manifest[field]) {
+				entries[path.Join(dir, target)] = true
+			}
+		}
+	}
+
+	return entries
+}
+
+func collectJSONStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case map[string]any:
+		values := []string{}
+		for _, nested := range v {
+			values = append(values, collectJSONStrings(nested)...)
+		}
+		return values
+	case []any:
+		values := []string{}
+		for _, nested := range v {
+			values = append(values, collectJSONStrings(nested)...)
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// isEntryFile: files something outside the import graph loads
+func isEntryFile(p string, lang parser.Language, content string, pathSet map[string]bool) bool {
+	base := path.Base(p)
+	dir := path.Dir(p)
+
+	switch lang {
+	case parser.LangGo:
+		if strings.HasSuffix(p, "_test.go") {
+			return true
+		}
+		pkgName := ""
+		if matches := goPackageClause.FindStringSubmatch(content); matches != nil {
+			pkgName = matches[1]
+		}
+		// package main, or a public package other modules may import
+		return pkgName == "main" || !isInternalPath(dir)
+
+	case parser.LangPython:
+		if strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py") || pyEntryFiles[base] {
+			return true
+		}
+		if strings.Contains("/"+p, "/migrations/") {
+			return true
+		}
+		for _, line := range strings.Split(content, "\n") {
+			if pyMainGuard.MatchString(line) {
+				return true
+			}
+		}
+		// Top-level package of a library
+		return base == "__init__.py" && !pathSet[path.Join(path.Dir(dir), "__init__.py")]
+
+	default:
+		if strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+			strings.Contains("/"+p, "/__tests__/") || strings.Contains(base, ".config.") || strings.HasPrefix(base, ".") {
+			return true
+		}
+
+		stem := strings.TrimSuffix(base, path.Ext(base))
+		if jsEntryStems[stem] && jsEntryDirs[dir] {
+			return true
+		}
+
+		// File-system routing (Next.js, Nuxt, SvelteKit, ...)
+		for _, segment := range strings.Split(dir, "/") {
+			if segment == "pages" || segment == "routes" {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Orphans runs the BFS from the entry files (plus extra ones) and returns
+// a verdict for every file in the graph: true when nothing reaches it
+func (g *ImportGraph) Orphans(extraEntries map[string]bool) map[string]bool {
+	if g == nil {
+		return nil
+	}
+
+	reached := make(map[string]bool)
+	queue := []string{}
+	for p := range g.files {
+		if g.entries[p] || extraEntries[p] {
+			reached[p] = true
+			queue = append(queue, p)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for next := range g.edges[current] {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	orphans := make(map[string]bool, len(g.files))
+	for p := range g.files {
+		orphans[p] = !reached[p]
+	}
+	return orphans
+}
+
+// applyOrphans marks analyzed files; files with an entrypoint function
+// (route handlers, __main__ calls) are entries too
+func applyOrphans(analyses map[string]*models.FileAnalysis, graph *ImportGraph) {
+	if graph == nil {
+		return
+	}
+
+	extraEntries := make(map[string]bool)
+	for p, analysis := range analyses {
+		for _, fn := range analysis.Functions {
+			if fn.Role == models.RoleEntrypoint {
+				extraEntries[p] = true
+				break
+			}
+		}
+	}
+
+	orphans := graph.Orphans(extraEntries)
+	for p, analysis := range analyses {
+		if orphan, ok := orphans[p]; ok {
+			analysis.Orphan = &orphan
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+func importGraphRepo(t *testing.T, files map[string]string) (*ImportGraph, []string) {
+	t.Helper()
+
+	repo := t.TempDir()
+	paths := []string{}
+	for p, content := range files {
+		full := filepath.Join(repo, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	return BuildImportGraph(repo, paths, parser.NewLanguageDetector(repo)), paths
+}
+
+func TestImportGraphOrphans(t *testing.T) {
+	g, _ := importGraphRepo(t, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+
+		// Go: main imports a package, package files link each other
+		"cmd/app/main.go":                   "package main\n\nimport \"example.com/app/internal/store\"\n\nfunc main() { store.Open() }\n",
+		"internal/store/store.go":           "package store\n\nfunc Open() {}\n",
+		"internal/store/cache.go":           "package store\n",
+		"internal/unused/unused.go":         "package unused\n",
+		"internal/testonly/fixture.go":      "package testonly\n",
+		"internal/testonly/fixture_test.go": "package testonly\n",
+		"pkg/api/api.go":                    "package api\n",
+
+		// JS/TS: relative, index, alias and ESM .js -> .ts
+		"src/index.ts":       "import { Button } from './ui'\nimport { format } from '@/lib/format'\nimport React from 'react'\nconst legacy = require('./legacy.js')\n",
+		"src/ui/index.tsx":   "export function Button() {}\n",
+		"src/lib/format.ts":  "export function format() {}\n",
+		"src/legacy.ts":      "export default 1\n",
+		"src/dead.ts":        "export function unused() {}\n",
+		"src/button.test.ts": "import { Button } from './ui'\n",
+
+		// Python: absolute, relative, parent-relative and package inits
+		"scripts/run.py":     "from tools.text import slug\nfrom . import helpers\nimport pkgs.a.b as job\n\nif __name__ == \"__main__\":\n    slug()\n",
+		"scripts/helpers.py": "def help(): pass\n",
+		"tools/__init__.py":  "",
+		"tools/text.py":      "def slug(): pass\n",
+		"tools/old.py":       "def old(): pass\n",
+		"pkgs/a/b.py":        "from ..c import d\n",
+		"pkgs/c.py":          "d = 1\n",
+		"pkgs/stale.py":      "# from pkgs import c\n",
+	})
+
+	want := map[string]bool{
+		"cmd/app/main.go":                   false,
+		"internal/store/store.go":           false,
+		"internal/store/cache.go":           false,
+		"internal/unused/unused.go":         true,
+		"internal/testonly/fixture.go":      false, // reached from its test
+		"internal/testonly/fixture_test.go": false, // tests are entries
+		"pkg/api/api.go":                    false, // public package, library API
+		"src/index.ts":                      false,
+		"src/ui/index.tsx":                  false,
+		"src/lib/format.ts":                 false,
+		"src/legacy.ts":                     false,
+		"src/dead.ts":                       true,
+		"src/button.test.ts":                false,
+		"scripts/run.py":                    false,
+		"scripts/helpers.py":                false,
+		"tools/__init__.py":                 false,
+		"tools/text.py":                     false,
+		"tools/old.py":                      true,
+		"pkgs/a/b.py":                       false,
+		"pkgs/c.py":                         false,
+		"pkgs/stale.py":                     true, // the import is commented out
+	}
+
+	orphans := g.Orphans(nil)
+	if len(orphans) != len(want) {
+		t.Errorf("graph has %d files, want %d: %v", len(orphans), len(want), orphans)
+	}
+	for p, wantOrphan := range want {
+		got, ok := orphans[p]
+		if !ok {
+			t.Errorf("%s: not in the graph", p)
+			continue
+		}
+		if got != wantOrphan {
+			t.Errorf("%s: orphan = %v, want %v", p, got, wantOrphan)
+		}
+	}
+
+	// A file with an entrypoint function (a route handler) is an entry too
+	if g.Orphans(map[string]bool{"src/dead.ts": true})["src/dead.ts"] {
+		t.Errorf("src/dead.ts: extra entries should be reached")
+	}
+}
+
+func TestIsEntryFileGo(t *testing.T) {
+	tests := []struct {
+		path, content string
+		want          bool
+	}{
+		{"cmd/tool/main.go", "package main\n", true},
+		{"internal/x/x_test.go", "package x\n", true},
+		{"internal/x/x.go", "package x\n", false},
+		{"app/internal/x/x.go", "package x\n", false},
+		{"store/store.go", "package store\n", true},
+		{"internals/x.go", "package x\n", true},
+	}
+
+	for _, tt := range tests {
+		if got := isEntryFile(tt.path, parser.LangGo, tt.content, nil); got != tt.want {
+			t.Errorf("isEntryFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveJSImport(t *testing.T) {
+	pathSet := map[string]bool{
+		"src/ui/Button.vue":  true,
+		"src/ui/index.ts":    true,
+		"src/lib/date.ts":    true,
+		"lib/shared.js":      true,
+		"src/pages/home.tsx": true,
+		"src/types/api.d.ts": true,
+	}
+
+	tests := []struct {
+		dir, specifier string
+		want           string
+	}{
+		{"src/pages", "../ui/Button.vue", "src/ui/Button.vue"},
+		{"src/pages", "../ui", "src/ui/index.ts"},
+		{"src/pages", "../lib/date.js", "src/lib/date.ts"},
+		{"src/pages", "@/lib/date", "src/lib/date.ts"},
+		{"src/pages", "~/lib/shared", "lib/shared.js"},
+		{"src/pages", "../types/api", "src/types/api.d.ts"},
+		{"src/pages", "./home", "src/pages/home.tsx"},
+		{"src/pages", "react", ""},
+		{"src/pages", "./missing", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := resolveJSImport(tt.dir, tt.specifier, pathSet)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("resolveJSImport(%q, %q) = %q, %v, want %q", tt.dir, tt.specifier, got, ok, tt.want)
+		}
+	}
+}
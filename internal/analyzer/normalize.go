@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: One Outlier Shouldn't Set the Scale
+//
+// Raw heat is unbounded, so scores are mapped to 0-100 against the other
+// files (or the other functions of a file):
+//
+//   max         raw / max · 100                 the hottest is 100; one outlier squashes the rest
+//   percentile  share of the others it beats    evenly spread, ranks only
+//   log         ln(1 + raw) / ln(1 + max) · 100 keeps the order and most of the shape
+//   zscore      z = (raw - mean) / stddev,      50 is average, ±3σ and beyond pin to 100 / 0
+//               clamped to ±3, mapped to 0-100
+//   absolute    raw                             comparable across analyses, not bounded
+//
+// Ignored nodes take no part in the scale and always score 0.
+
+// Clamp for the zscore normalization, in standard deviations
+const zScoreClamp = 3.0
+
+// Normalize maps raw scores onto the configured scale; skip marks ignored nodes
+func (b heatModelBase) Normalize(raw []float64, skip []bool) []float64 {
+	scores := make([]float64, len(raw))
+
+	values := make([]float64, 0, len(raw))
+	for i, r := range raw {
+		if !skip[i] {
+			values = append(values, r)
+		}
+	}
+
+	maxRaw, sum := 0.0, 0.0
+	for _, v := range values {
+		maxRaw = math.Max(maxRaw, v)
+		sum += v
+	}
+
+	var sorted []float64
+	mean, stddev := 0.0, 0.0
+	switch b.normalization {
+	case models.NormalizationPercentile:
+		sorted = append(sorted, values...)
+		sort.Float64s(sorted)
+	case models.NormalizationZScore:
+		if len(values) > 0 {
+			mean = sum / float64(len(values))
+			for _, v := range values {
+				stddev += (v - mean) * (v - mean)
+			}
+			stddev = math.Sqrt(stddev / float64(len(values)))
+		}
+	}
+
+	for i, r := range raw {
+		if skip[i] {
+			continue
+		}
+
+		switch b.normalization {
+		case models.NormalizationPercentile:
+			scores[i] = percentileRank(sorted, r)
+		case models.NormalizationLog:
+			scores[i] = MinScore
+			if maxRaw > 0 {
+				scores[i] = math.Log1p(r) / math.Log1p(maxRaw) * 100
+			}
+		case models.NormalizationZScore:
+			z := 0.0
+			if stddev > 0 {
+				z = math.Max(-zScoreClamp, math.Min(zScoreClamp, (r-mean)/stddev))
+			}
+			scores[i] = (z + zScoreClamp) / (2 * zScoreClamp) * 100
+		case models.NormalizationAbsolute:
+			scores[i] = r
+		default:
+			// Normalize: (raw / max) * 100
+			// This ensures hottest file = 100, coldest = relative to that
+			scores[i] = MinScore
+			if maxRaw > 0 {
+				scores[i] = r / maxRaw * 100
+			}
+		}
+	}
+
+	return scores
+}
+
+// percentileRank: share of the other values below v, ties counting half
+func percentileRank(sorted []float64, v float64) float64 {
+	if len(sorted) < 2 {
+		return 100
+	}
+
+	below := sort.SearchFloat64s(sorted, v)
+	equal := sort.Search(len(sorted), func(i int) bool { return sorted[i] > v }) - below
+
+	return (float64(below) + float64(equal-1)/2) / float64(len(sorted)-1) * 100
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalization string
+		raw           []float64
+		skip          []bool
+		want          []float64
+	}{
+		{"max", models.NormalizationMax, []float64{0, 5, 10}, nil, []float64{0, 50, 100}},
+		{"max, all zero", models.NormalizationMax, []float64{0, 0}, nil, []float64{MinScore, MinScore}},
+		{"max, single", models.NormalizationMax, []float64{4}, nil, []float64{100}},
+		{"max, skipped", models.NormalizationMax, []float64{10, 100, 5}, []bool{false, true, false}, []float64{100, 0, 50}},
+
+		{"percentile", models.NormalizationPercentile, []float64{3, 1, 2}, nil, []float64{100, 0, 50}},
+		{"percentile, ties", models.NormalizationPercentile, []float64{1, 1, 5}, nil, []float64{25, 25, 100}},
+		{"percentile, all zero", models.NormalizationPercentile, []float64{0, 0, 0}, nil, []float64{50, 50, 50}},
+		{"percentile, single", models.NormalizationPercentile, []float64{4}, nil, []float64{100}},
+		{"percentile, skipped out of the scale", models.NormalizationPercentile, []float64{1, 1000, 2},
+			[]bool{false, true, false}, []float64{0, 0, 100}},
+
+		// ln(1 + 3) / ln(1 + 15) = 0.5
+		{"log", models.NormalizationLog, []float64{0, 3, 15}, nil, []float64{0, 50, 100}},
+		{"log, all zero", models.NormalizationLog, []float64{0, 0}, nil, []float64{MinScore, MinScore}},
+		{"log, single", models.NormalizationLog, []float64{7}, nil, []float64{100}},
+		{"log, skipped", models.NormalizationLog, []float64{3, 99, 15}, []bool{false, true, false}, []float64{50, 0, 100}},
+
+		// mean 2, stddev 1: z = ±1
+		{"zscore", models.NormalizationZScore, []float64{1, 3}, nil, []float64{100.0 / 3, 200.0 / 3}},
+		// mean 10, stddev 30: the outlier sits at +3σ, the rest at -1/3σ
+		{"zscore, outlier", models.NormalizationZScore, []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 100}, nil,
+			[]float64{400.0 / 9, 400.0 / 9, 400.0 / 9, 400.0 / 9, 400.0 / 9, 400.0 / 9, 400.0 / 9, 400.0 / 9, 400.0 / 9, 100}},
+		{"zscore, all zero", models.NormalizationZScore, []float64{0, 0}, nil, []float64{50, 50}},
+		{"zscore, single", models.NormalizationZScore, []float64{4}, nil, []float64{50}},
+		{"zscore, skipped out of the scale", models.NormalizationZScore, []float64{1, 1000, 3},
+			[]bool{false, true, false}, []float64{100.0 / 3, 0, 200.0 / 3}},
+
+		{"absolute", models.NormalizationAbsolute, []float64{0, 2.5, 400}, nil, []float64{0, 2.5, 400}},
+		{"absolute, skipped", models.NormalizationAbsolute, []float64{2.5, 400}, []bool{true, false}, []float64{0, 400}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := NewHeatModel(&models.HeatModelConfig{Normalization: tt.normalization})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			skip := tt.skip
+			if skip == nil {
+				skip = make([]bool, len(tt.raw))
+			}
+			got := model.Normalize(tt.raw, skip)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Normalize(%v) = %v, want %v", tt.raw, got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("Normalize(%v) = %v, want %v", tt.raw, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeEmpty(t *testing.T) {
+	for _, normalization := range []string{models.NormalizationMax, models.NormalizationPercentile,
+		models.NormalizationLog, models.NormalizationZScore, models.NormalizationAbsolute} {
+		model, _ := NewHeatModel(&models.HeatModelConfig{Normalization: normalization})
+		if got := model.Normalize(nil, nil); len(got) != 0 {
+			t.Errorf("%s: Normalize(nil) = %v", normalization, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// LEARNING MOMENT: Who Would We Miss?
+//
+// Heat says how much a file changes, not who understands it. Counting each
+// author's commits on a file (folders: summed over their files) gives:
+//
+//   top author share   60% of the changes by one person
+//   authors for 80%    how many people it takes to cover most of the history
+//   bus factor         fewest authors who made more than half the changes;
+//                      lose them and most of the knowledge leaves with them
+//
+//   alice 6, bob 3, carol 1  ->  share 60%, authors for 80% = 2, bus factor = 1
+//
+// Knowledge is at risk when the top author hasn't committed anywhere on the
+// branch for knowledgeAtRiskDays (90 by default): time to plan a handover.
+// Only the window's commits are read, but that loses nothing: an author with
+// a share of a node committed inside the window, so their latest commit is
+// in it too. It does mean a window shorter than knowledgeAtRiskDays never
+// flags anyone.
+
+// Built-in days without a commit before a top author counts as gone
+const defaultKnowledgeAtRiskDays = 90
+
+// ValidateOwnershipRules checks the ownership section of a rules config
+func ValidateOwnershipRules(rules models.OwnershipRules) error {
+	if days := rules.KnowledgeAtRiskDays; days != nil && *days <= 0 {
+		return fmt.Errorf("ownership: knowledgeAtRiskDays must be positive, got %d", *days)
+	}
+	return nil
+}
+
+// ownershipScorer turns commits per author into ownership metrics
+type ownershipScorer struct {
+	lastCommit map[string]time.Time // author -> latest commit on the branch, within the window
+	now        time.Time
+	riskDays   int
+}
+
+func newOwnershipScorer(result *models.AnalysisResult) *ownershipScorer {
+	s := &ownershipScorer{
+		lastCommit: result.AuthorLastCommit,
+		now:        result.AnalyzedAt,
+		riskDays:   defaultKnowledgeAtRiskDays,
+	}
+	if result.Rules != nil && result.Rules.Ownership.KnowledgeAtRiskDays != nil {
+		s.riskDays = *result.Rules.Ownership.KnowledgeAtRiskDays
+	}
+	return s
+}
+
+// score returns nil when nobody changed the node
+func (s *ownershipScorer) score(authors map[string]int) *models.Ownership {
+	names := make([]string, 0, len(authors))
+	total := 0
+	for name, count := range authors {
+		if count <= 0 {
+			continue
+		}
+		names = append(names, name)
+		total += count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// Most commits first, ties by name so the top author is stable
+	sort.Slice(names, func(i, j int) bool {
+		if authors[names[i]] != authors[names[j]] {
+			return authors[names[i]] > authors[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	top := names[0]
+	o := &models.Ownership{
+		Authors:             len(names),
+		TopAuthor:           top,
+		TopAuthorShare:      float64(authors[top]) / float64(total) * 100,
+		TopAuthorLastCommit: s.lastCommit[top],
+	}
+
+	covered := 0
+	for i, name := range names {
+		covered += authors[name]
+		if o.BusFactor == 0 && covered*2 > total {
+			o.BusFactor = i + 1
+		}
+		if o.AuthorsFor80 == 0 && covered*5 >= total*4 {
+			o.AuthorsFor80 = i + 1
+		}
+	}
+
+	if last, ok := s.lastCommit[top]; ok {
+		o.KnowledgeAtRisk = s.now.Sub(last) >= time.Duration(s.riskDays)*24*time.Hour
+	}
+
+	return o
+}
+
+// addAuthors sums commit counts by author into dst (allocated on first use)
+func addAuthors(dst, src map[string]int) map[string]int {
+	for name, count := range src {
+		if dst == nil {
+			dst = make(map[string]int)
+		}
+		dst[name] += count
+	}
+	return dst
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func TestOwnershipScore(t *testing.T) {
+	tests := []struct {
+		name         string
+		authors      map[string]int
+		top          string
+		share        float64
+		authorsFor80 int
+		busFactor    int
+	}{
+		{"learning moment example", map[string]int{"alice": 6, "bob": 3, "carol": 1}, "alice", 60, 2, 1},
+		{"single author", map[string]int{"alice": 4}, "alice", 100, 1, 1},
+		// exactly half is not more than half
+		{"even split", map[string]int{"alice": 2, "bob": 2}, "alice", 50, 2, 2},
+		// exactly 80% is enough
+		{"80% boundary", map[string]int{"alice": 4, "bob": 4, "carol": 2}, "alice", 40, 2, 2},
+		{"long tail", map[string]int{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1}, "a", 20, 4, 3},
+		{"zero counts ignored", map[string]int{"alice": 0, "bob": 3}, "bob", 100, 1, 1},
+	}
+
+	s := newOwnershipScorer(&models.AnalysisResult{AnalyzedAt: time.Now()})
+	for _, tt := range tests {
+		o := s.score(tt.authors)
+		if o == nil {
+			t.Fatalf("%s: no ownership", tt.name)
+		}
+		if o.TopAuthor != tt.top || math.Abs(o.TopAuthorShare-tt.share) > 1e-9 ||
+			o.AuthorsFor80 != tt.authorsFor80 || o.BusFactor != tt.busFactor {
+			t.Errorf("%s: got top %s %.1f%%, for80 %d, bus %d, want %s %.1f%%, %d, %d", tt.name,
+				o.TopAuthor, o.TopAuthorShare, o.AuthorsFor80, o.BusFactor, tt.top, tt.share, tt.authorsFor80, tt.busFactor)
+		}
+	}
+
+	if o := s.score(nil); o != nil {
+		t.Errorf("no authors: got %+v, want nil", o)
+	}
+	if o := s.score(map[string]int{"alice": 0}); o != nil {
+		t.Errorf("no commits: got %+v, want nil", o)
+	}
+}
+
+func TestOwnershipKnowledgeAtRisk(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+	sixty := 60
+	result := &models.AnalysisResult{
+		AnalyzedAt: now,
+		AuthorLastCommit: map[string]time.Time{
+			"gone":   now.AddDate(0, 0, -90),
+			"recent": now.AddDate(0, 0, -89),
+			"quiet":  now.AddDate(0, 0, -60),
+		},
+	}
+
+	tests := []struct {
+		author string
+		rules  *models.RulesConfig
+		want   bool
+	}{
+		{"gone", nil, true},
+		{"recent", nil, false},
+		{"quiet", nil, false},
+		{"quiet", &models.RulesConfig{Ownership: models.OwnershipRules{KnowledgeAtRiskDays: &sixty}}, true},
+		{"unknown", nil, false},
+	}
+
+	for _, tt := range tests {
+		result.Rules = tt.rules
+		o := newOwnershipScorer(result).score(map[string]int{tt.author: 2, "helper": 1})
+		if o.KnowledgeAtRisk != tt.want {
+			t.Errorf("%s: at risk = %v, want %v", tt.author, o.KnowledgeAtRisk, tt.want)
+		}
+		if !o.TopAuthorLastCommit.Equal(result.AuthorLastCommit[tt.author]) {
+			t.Errorf("%s: last commit = %v", tt.author, o.TopAuthorLastCommit)
+		}
+	}
+}
+
+func TestValidateOwnershipRules(t *testing.T) {
+	zero, month := 0, 30
+	if err := ValidateOwnershipRules(models.OwnershipRules{KnowledgeAtRiskDays: &zero}); err == nil {
+		t.Errorf("zero days: no error")
+	}
+	if err := ValidateOwnershipRules(models.OwnershipRules{KnowledgeAtRiskDays: &month}); err != nil {
+		t.Errorf("30 days: %v", err)
+	}
+}
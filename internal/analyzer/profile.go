@@ -0,0 +1,276 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+// LEARNING MOMENT: Runtime Evidence Beats Git Silence
+//
+// A function nobody edited in a year can still run a million times a day.
+// A pprof profile is a list of samples, each one a call stack plus values
+// (CPU time, bytes allocated, ...). For every file and function:
+//
+//   share = Σ value of samples with it anywhere on the stack / Σ value of all samples
+//
+// (cumulative share - a dispatcher that only calls hot code is hot too).
+//
+// Matching profile frames to parser units:
+// 1. Filename -> repo path (longest common path suffix, like coverage)
+// 2. Symbol -> unit name: "github.com/x/y/models.(*User).GetName" -> "(*User).GetName"
+//    (drop the package path and generic instantiations like [...])
+// 3. No such unit? Numbered inits ("init.0") and package-level literals are
+//    named differently by the compiler - fall back to the innermost unit
+//    containing the sampled line
+// 4. Closures ("Handler.func1") always go by line: the compiler numbers every
+//    literal in a function, deferred and immediately called ones too, the
+//    parser only the ones it names, so the same name can mean another closure
+//
+// Profiles are sampled, so zero samples proves nothing. Samples prove a lot:
+// a function with any is never reported dead.
+
+// RuntimeProfiles holds parsed pprof profiles
+type RuntimeProfiles struct {
+	profiles []*profile.Profile
+}
+
+// ParseProfiles parses pprof profiles (gzipped or not). Returns nil for no profiles.
+func ParseProfiles(reports []models.ProfileReport) (*RuntimeProfiles, error) {
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	rp := &RuntimeProfiles{}
+	for i, report := range reports {
+		name := report.Name
+		if name == "" {
+			name = fmt.Sprintf("profile %d", i+1)
+		}
+
+		p, err := profile.Parse(bytes.NewReader(report.Content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(p.SampleType) == 0 {
+			return nil, fmt.Errorf("%s: profile has no sample types", name)
+		}
+
+		rp.profiles = append(rp.profiles, p)
+	}
+
+	return rp, nil
+}
+
+// frameHit is one file/unit a profile location resolved to
+type frameHit struct {
+	path string
+	unit *models.FunctionStats // nil when no unit matched
+}
+
+// apply sets runtime shares on Go files and functions. With several
+// profiles (CPU and heap, say) the highest share wins.
+func (rp *RuntimeProfiles) apply(analyses map[string]*models.FileAnalysis, repoPaths []string) {
+	if rp == nil {
+		return
+	}
+
+	resolver := newPathResolver(repoPaths)
+	fileShares := make(map[string]float64)
+	unitShares := make(map[*models.FunctionStats]float64)
+
+	for _, p := range rp.profiles {
+		index := sampleIndex(p)
+		locations := make(map[uint64][]frameHit)
+
+		total := 0.0
+		fileValues := make(map[string]float64)
+		unitValues := make(map[*models.FunctionStats]float64)
+
+		for _, sample := range p.Sample {
+			value := float64(sample.Value[index])
+			if value <= 0 {
+				continue
+			}
+			total += value
+
+			// Recursion puts a function on the stack more than once - count it once
+			seenFiles := make(map[string]bool)
+			seenUnits := make(map[*models.FunctionStats]bool)
+
+			for _, loc := range sample.Location {
+				hits, cached := locations[loc.ID]
+				if !cached {
+					hits = resolveLocation(loc, analyses, resolver)
+					locations[loc.ID] = hits
+				}
+
+				for _, hit := range hits {
+					if !seenFiles[hit.path] {
+						seenFiles[hit.path] = true
+						fileValues[hit.path] += value
+					}
+					if hit.unit != nil && !seenUnits[hit.unit] {
+						seenUnits[hit.unit] = true
+						unitValues[hit.unit] += value
+					}
+				}
+			}
+		}
+
+		if total == 0 {
+			continue
+		}
+		for path, value := range fileValues {
+			fileShares[path] = math.Max(fileShares[path], value/total*100)
+		}
+		for unit, value := range unitValues {
+			unitShares[unit] = math.Max(unitShares[unit], value/total*100)
+		}
+	}
+
+	for path, analysis := range analyses {
+		if analysis.Language != "go" {
+			continue
+		}
+
+		share := fileShares[path]
+		analysis.RuntimeShare = &share
+
+		for _, fn := range analysis.Functions {
+			share := unitShares[fn]
+			fn.RuntimeShare = &share
+		}
+	}
+}
+
+// sampleIndex picks the value to weigh samples by. Heap profiles default
+// to in-use space, but for "does it run" allocations are what count.
+func sampleIndex(p *profile.Profile) int {
+	preferred := p.DefaultSampleType
+	for _, st := range p.SampleType {
+		if st.Type == "alloc_space" {
+			preferred = st.Type
+		}
+	}
+
+	for i, st := range p.SampleType {
+		if st.Type == preferred {
+			return i
+		}
+	}
+
+	// CPU profiles list samples/count first and cpu/nanoseconds last
+	return len(p.SampleType) - 1
+}
+
+// resolveLocation maps a location (with its inlined frames) to files and units
+func resolveLocation(loc *profile.Location, analyses map[string]*models.FileAnalysis, resolver *pathResolver) []frameHit {
+	hits := []frameHit{}
+
+	for _, line := range loc.Line {
+		if line.Function == nil || line.Function.Filename == "" || isDependencyFrame(line.Function) {
+			continue
+		}
+
+		path, ok := resolver.resolve(line.Function.Filename)
+		if !ok {
+			continue // runtime, stdlib, dependencies
+		}
+
+		hit := frameHit{path: path}
+		if analysis, ok := analyses[path]; ok {
+			hit.unit = matchProfileFunction(analysis.Functions, line.Function.Name, int(line.Line))
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits
+}
+
+// isDependencyFrame spots module cache and standard library frames, which
+// could otherwise suffix-match a repo file with a common name (main.go)
+func isDependencyFrame(fn *profile.Function) bool {
+	filename := strings.ReplaceAll(fn.Filename, "\\", "/")
+	if strings.Contains(filename, "/pkg/mod/") {
+		return true
+	}
+
+	// Standard library: no dot in the first path element, source under GOROOT/src
+	pkgPath := fn.Name
+	if i := strings.LastIndex(pkgPath, "/"); i >= 0 {
+		if j := strings.Index(pkgPath[i:], "."); j >= 0 {
+			pkgPath = pkgPath[:i+j]
+		}
+	} else if j := strings.Index(pkgPath, "."); j >= 0 {
+		pkgPath = pkgPath[:j]
+	}
+
+	first, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".") && strings.Contains(filename, "/src/"+pkgPath+"/")
+}
+
+// Compiler closure names: "Handler.func1", "Handler.func1.2", "(*T).Run.func3"
+var compilerClosureName = regexp.MustCompile(`\.func\d+(?:\.|$)`)
+
+// matchProfileFunction finds the unit for a profile symbol, by name first, then by line
+func matchProfileFunction(functions []*models.FunctionStats, symbol string, line int) *models.FunctionStats {
+	name := profileUnitName(symbol)
+	if !compilerClosureName.MatchString(name) {
+		for _, fn := range functions {
+			if fn.Name == name {
+				return fn
+			}
+		}
+	}
+
+	var innermost *models.FunctionStats
+	for _, fn := range functions {
+		if fn.Type == string(parser.TypeStruct) || fn.Type == string(parser.TypeInterface) {
+			continue
+		}
+		if line < fn.LineStart || line > fn.LineEnd {
+			continue
+		}
+		if innermost == nil || fn.LineEnd-fn.LineStart < innermost.LineEnd-innermost.LineStart {
+			innermost = fn
+		}
+	}
+
+	return innermost
+}
+
+// profileUnitName turns a pprof symbol into a GoParser name:
+// "github.com/x/y/models.(*Cache[...]).Get" -> "(*Cache).Get"
+func profileUnitName(symbol string) string {
+	// Generic instantiations may contain paths and dots of their own
+	var b strings.Builder
+	depth := 0
+	for _, r := range symbol {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
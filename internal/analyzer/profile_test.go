@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+func TestProfileUnitName(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"main.main", "main"},
+		{"github.com/x/y/models.NewUser", "NewUser"},
+		{"github.com/x/y/models.(*User).GetName", "(*User).GetName"},
+		{"github.com/x/y/models.User.String", "User.String"},
+		{"github.com/x/y/cache.(*Cache[go.shape.string,go.shape.int]).Get", "(*Cache).Get"},
+		{"github.com/x/y/cache.(*Cache[github.com/x/y/models.Key,go.shape.*uint8]).Get", "(*Cache).Get"},
+		{"github.com/x/y/cache.Map[go.shape.int,github.com/x/y/models.Key]", "Map"},
+		{"github.com/x/y/api.Handler.func1", "Handler.func1"},
+		{"github.com/x/y/api.(*Server).Start.func2.1", "(*Server).Start.func2.1"},
+		{"github.com/x/y/cache.init.0", "init.0"},
+	}
+
+	for _, tt := range tests {
+		if got := profileUnitName(tt.symbol); got != tt.want {
+			t.Errorf("profileUnitName(%q) = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestMatchProfileFunction(t *testing.T) {
+	unit := func(name string, unitType parser.FunctionType, start, end int) *models.FunctionStats {
+		return &models.FunctionStats{Name: name, Type: string(unitType), LineStart: start, LineEnd: end}
+	}
+
+	// func Handler() {              // 1-20
+	//     defer func() {...}()      // 2-4    compiler: Handler.func1, parser: part of Handler
+	//     mux.HandleFunc(func() {   // 6-12   compiler: Handler.func2, parser: Handler.func1
+	//         go func() {...}()     // 8-10   compiler: Handler.func2.1
+	//     })
+	// }
+	// type Cache[K comparable, V any] struct { ... }   // 22-25
+	// func (c *Cache[K, V]) Get(k K) V { ... }          // 27-35
+	// func (v Value) String() string { ... }            // 37-39
+	handler := unit("Handler", parser.TypeFunction, 1, 20)
+	handlerFunc := unit("Handler.func1", parser.TypeClosure, 6, 12)
+	cache := unit("Cache", parser.TypeStruct, 22, 25)
+	get := unit("(*Cache).Get", parser.TypeMethod, 27, 35)
+	str := unit("(Value).String", parser.TypeMethod, 37, 39)
+	functions := []*models.FunctionStats{cache, handler, handlerFunc, get, str}
+
+	tests := []struct {
+		name   string
+		symbol string
+		line   int
+		want   *models.FunctionStats
+	}{
+		{"plain function", "github.com/x/y/api.Handler", 15, handler},
+		{"generic pointer receiver", "github.com/x/y/api.(*Cache[go.shape.string,go.shape.int]).Get", 30, get},
+		{"name wins over line", "github.com/x/y/api.(*Cache[go.shape.string,go.shape.int]).Get", 15, get},
+		{"value receiver by line", "github.com/x/y/api.Value.String", 38, str},
+		{"deferred closure is not the parser's func1", "github.com/x/y/api.Handler.func1", 3, handler},
+		{"argument closure by line", "github.com/x/y/api.Handler.func2", 7, handlerFunc},
+		{"nested closure by line", "github.com/x/y/api.Handler.func2.1", 9, handlerFunc},
+		{"numbered init by line", "github.com/x/y/api.init.0", 50, nil},
+		{"struct lines never match", "github.com/x/y/api.Cache.func1", 23, nil},
+	}
+
+	for _, tt := range tests {
+		if got := matchProfileFunction(functions, tt.symbol, tt.line); got != tt.want {
+			t.Errorf("%s: matched %v, want %v", tt.name, statsName(got), statsName(tt.want))
+		}
+	}
+}
+
+func statsName(fn *models.FunctionStats) string {
+	if fn == nil {
+		return "<nil>"
+	}
+	return fn.Name
+}
@@ -0,0 +1,342 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/pkg"
+)
+
+// RulesFileNames are looked up at the root of the analyzed repository
+var RulesFileNames = []string{".firesight.yaml", ".firesight.yml"}
+
+// DefaultRuleName is recorded when no rule matched a node
+const DefaultRuleName = "default"
+
+type nodeKind int
+
+const (
+	fileNode nodeKind = iota
+	functionNode
+)
+
+// Built-in settings, the values the analyzer always used:
+// files are dead on 2 of 3 signals (6 months, < 3 changes, single author),
+// functions on both of 2 (6 months, < 2 changes).
+// With a coverage report "uncovered" joins both: cold and untested is dead.
+// An orphan file (nobody imports it) needs just one more signal.
+// A Go function the call graph can't reach, or a JS/TS/Python function nothing
+// mentions, is dead once it's also inactive.
+var builtinSettings = map[nodeKind]DeadCodePolicy{
+	fileNode: {
+		InactiveDays: 180,
+		MinChanges:   3,
+		Threshold:    2,
+		Weights: map[models.DeadCodeReason]float64{
+			models.DeadCodeReasonInactive:     1,
+			models.DeadCodeReasonFewChanges:   1,
+			models.DeadCodeReasonSingleAuthor: 1,
+			models.DeadCodeReasonUncovered:    1,
+			models.DeadCodeReasonOrphan:       1.5,
+		},
+	},
+	functionNode: {
+		InactiveDays: 180,
+		MinChanges:   2,
+		Threshold:    2,
+		Weights: map[models.DeadCodeReason]float64{
+			models.DeadCodeReasonInactive:     1,
+			models.DeadCodeReasonFewChanges:   1,
+			models.DeadCodeReasonUncovered:    1,
+			models.DeadCodeReasonUnreachable:  1,
+			models.DeadCodeReasonUnreferenced: 1,
+		},
+	},
+}
+
+// DeadCodePolicy is the resolved set of settings for one node
+type DeadCodePolicy struct {
+	Rule         string // which rule decided
+	Never        bool
+	InactiveDays int
+	MinChanges   int
+	Threshold    float64
+	Weights      map[models.DeadCodeReason]float64
+
+	IncludeEntrypoints bool
+	IncludeExported    bool
+}
+
+// excludes: entrypoints and exported API can't be judged by what the repo
+// itself does with them, unless the rule says so
+func (p *DeadCodePolicy) excludes(role models.FunctionRole) bool {
+	switch role {
+	case models.RoleEntrypoint:
+		return !p.IncludeEntrypoints
+	case models.RoleExported:
+		return !p.IncludeExported
+	default:
+		return false
+	}
+}
+
+// decide: weighted sum of fired signals against the threshold
+func (p *DeadCodePolicy) decide(reasons []models.DeadCodeReason) bool {
+	if p.Never {
+		return false
+	}
+
+	score := 0.0
+	for _, reason := range reasons {
+		score += p.Weights[reason]
+	}
+
+	return score >= p.Threshold
+}
+
+// RuleEngine matches nodes against the configured rules
+type RuleEngine struct {
+	defaults models.DeadCodeSettings
+	rules    []compiledRule
+}
+
+type compiledRule struct {
+	name      string
+	rule      models.DeadCodeRule
+	globs     []*pkg.Glob
+	languages map[string]bool
+}
+
+// NewRuleEngine compiles a rules config. A nil config gives the built-in behavior.
+func NewRuleEngine(cfg *models.RulesConfig) (*RuleEngine, error) {
+	engine := &RuleEngine{}
+	if cfg == nil {
+		return engine, nil
+	}
+
+	// Commit patterns live in the same file, reject a broken one here too
+	if _, err := NewCommitClassifier(cfg); err != nil {
+		return nil, err
+	}
+	if err := ValidateOwnershipRules(cfg.Ownership); err != nil {
+		return nil, err
+	}
+
+	if err := validateWeights(cfg.DeadCode.Defaults.Weights); err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+	engine.defaults = cfg.DeadCode.Defaults
+
+	for i, rule := range cfg.DeadCode.Rules {
+		compiled := compiledRule{
+			name:      rule.Name,
+			rule:      rule,
+			languages: make(map[string]bool),
+		}
+		if compiled.name == "" {
+			switch {
+			case len(rule.Paths) > 0:
+				compiled.name = strings.Join(rule.Paths, ", ")
+			case len(rule.Languages) > 0:
+				compiled.name = "languages: " + strings.Join(rule.Languages, ", ")
+			default:
+				compiled.name = fmt.Sprintf("rule %d", i+1)
+			}
+		}
+
+		if err := validateWeights(rule.Weights); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", compiled.name, err)
+		}
+		for _, pattern := range rule.Paths {
+			glob, err := pkg.CompileGlob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid path %q: %w", compiled.name, pattern, err)
+			}
+			compiled.globs = append(compiled.globs, glob)
+		}
+		for _, lang := range rule.Languages {
+			compiled.languages[strings.ToLower(lang)] = true
+		}
+
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	return engine, nil
+}
+
+// validateWeights rejects reasons no signal produces, a typo would do nothing
+func validateWeights(weights map[models.DeadCodeReason]float64) error {
+	for reason := range weights {
+		if !slices.Contains(models.DeadCodeReasons, reason) {
+			return fmt.Errorf("unknown reason %q in weights (want one of %v)", reason, models.DeadCodeReasons)
+		}
+	}
+	return nil
+}
+
+// Policy resolves the settings for a node. Precedence:
+// first matching rule > config defaults > built-in values
+func (re *RuleEngine) Policy(path, language string, kind nodeKind) *DeadCodePolicy {
+	builtin := builtinSettings[kind]
+	policy := &DeadCodePolicy{
+		Rule:         DefaultRuleName,
+		InactiveDays: builtin.InactiveDays,
+		MinChanges:   builtin.MinChanges,
+		Threshold:    builtin.Threshold,
+		Weights:      make(map[models.DeadCodeReason]float64),
+	}
+	for reason, weight := range builtin.Weights {
+		policy.Weights[reason] = weight
+	}
+
+	policy.apply(re.defaults)
+
+	for _, rule := range re.rules {
+		if !rule.matches(path, language) {
+			continue
+		}
+
+		policy.Rule = rule.name
+		policy.Never = rule.rule.Never
+		policy.apply(rule.rule.DeadCodeSettings)
+		break
+	}
+
+	return policy
+}
+
+func (p *DeadCodePolicy) apply(settings models.DeadCodeSettings) {
+	if settings.InactiveDays != nil {
+		p.InactiveDays = *settings.InactiveDays
+	}
+	if settings.MinChanges != nil {
+		p.MinChanges = *settings.MinChanges
+	}
+	if settings.Threshold != nil {
+		p.Threshold = *settings.Threshold
+	}
+	for reason, weight := range settings.Weights {
+		p.Weights[reason] = weight
+	}
+	if settings.IncludeEntrypoints != nil {
+		p.IncludeEntrypoints = *settings.IncludeEntrypoints
+	}
+	if settings.IncludeExported != nil {
+		p.IncludeExported = *settings.IncludeExported
+	}
+}
+
+func (cr *compiledRule) matches(path, language string) bool {
+	if len(cr.globs) > 0 {
+		matched := false
+		for _, glob := range cr.globs {
+			if glob.MatchOrParent(path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(cr.languages) > 0 && !cr.languages[strings.ToLower(language)] {
+		return false
+	}
+
+	return true
+}
+
+// LoadRulesFile reads .firesight.yaml from the repository root.
+// Returns nil (and no error) when the repo has none.
+func LoadRulesFile(repoPath string) (*models.RulesConfig, error) {
+	for _, name := range RulesFileNames {
+		content, err := os.ReadFile(filepath.Join(repoPath, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cfg := &models.RulesConfig{}
+		if err := yaml.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return cfg, nil
+	}
+
+	return nil, nil
+}
+
+// MergeRules puts request rules in front of the repo's, and lets request
+// defaults override repo defaults field by field
+func MergeRules(request, repo *models.RulesConfig) *models.RulesConfig {
+	if request == nil {
+		return repo
+	}
+	if repo == nil {
+		return request
+	}
+
+	merged := &models.RulesConfig{}
+	merged.DeadCode.Rules = append(append(merged.DeadCode.Rules, request.DeadCode.Rules...), repo.DeadCode.Rules...)
+
+	defaults := repo.DeadCode.Defaults
+	override := request.DeadCode.Defaults
+	if override.InactiveDays != nil {
+		defaults.InactiveDays = override.InactiveDays
+	}
+	if override.MinChanges != nil {
+		defaults.MinChanges = override.MinChanges
+	}
+	if override.Threshold != nil {
+		defaults.Threshold = override.Threshold
+	}
+	if len(override.Weights) > 0 {
+		weights := make(map[models.DeadCodeReason]float64)
+		for reason, weight := range repo.DeadCode.Defaults.Weights {
+			weights[reason] = weight
+		}
+		for reason, weight := range override.Weights {
+			weights[reason] = weight
+		}
+		defaults.Weights = weights
+	}
+	if override.IncludeEntrypoints != nil {
+		defaults.IncludeEntrypoints = override.IncludeEntrypoints
+	}
+	if override.IncludeExported != nil {
+		defaults.IncludeExported = override.IncludeExported
+	}
+	merged.DeadCode.Defaults = defaults
+
+	merged.Ownership = repo.Ownership
+	if request.Ownership.KnowledgeAtRiskDays != nil {
+		merged.Ownership.KnowledgeAtRiskDays = request.Ownership.KnowledgeAtRiskDays
+	}
+
+	// Commit patterns: request first, then the repo's
+	for _, kind := range models.CommitKinds {
+		if patterns := append(append([]string{}, request.Commits.Patterns[kind]...), repo.Commits.Patterns[kind]...); len(patterns) > 0 {
+			if merged.Commits.Patterns == nil {
+				merged.Commits.Patterns = make(map[models.CommitKind][]string)
+			}
+			merged.Commits.Patterns[kind] = patterns
+		}
+		if keys := append(append([]string{}, request.Commits.IssueKeys[kind]...), repo.Commits.IssueKeys[kind]...); len(keys) > 0 {
+			if merged.Commits.IssueKeys == nil {
+				merged.Commits.IssueKeys = make(map[models.CommitKind][]string)
+			}
+			merged.Commits.IssueKeys[kind] = keys
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,259 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func boolPtr(v bool) *bool        { return &v }
+
+func TestRulePolicyPrecedence(t *testing.T) {
+	cfg := &models.RulesConfig{DeadCode: models.DeadCodeRules{
+		Defaults: models.DeadCodeSettings{
+			InactiveDays: intPtr(120),
+			Weights:      map[models.DeadCodeReason]float64{models.DeadCodeReasonSingleAuthor: 0.5},
+		},
+		Rules: []models.DeadCodeRule{
+			{Name: "migrations", Paths: []string{"migrations/**"}, Never: true},
+			{Paths: []string{"internal/legacy/**"}, Languages: []string{"Go"}, DeadCodeSettings: models.DeadCodeSettings{
+				InactiveDays: intPtr(90),
+				Threshold:    floatPtr(1),
+			}},
+			{Paths: []string{"internal/legacy/**"}, DeadCodeSettings: models.DeadCodeSettings{MinChanges: intPtr(10)}},
+			{Languages: []string{"python"}, DeadCodeSettings: models.DeadCodeSettings{
+				Weights:         map[models.DeadCodeReason]float64{models.DeadCodeReasonFewChanges: 2},
+				IncludeExported: boolPtr(true),
+			}},
+		},
+	}}
+
+	engine, err := NewRuleEngine(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		path, lang   string
+		kind         nodeKind
+		rule         string
+		never        bool
+		inactiveDays int
+		minChanges   int
+		threshold    float64
+		weights      map[models.DeadCodeReason]float64 // checked entries only
+		exported     bool
+	}{
+		{
+			name: "config defaults over built-ins", path: "cmd/main.go", lang: "go", kind: fileNode,
+			rule: DefaultRuleName, inactiveDays: 120, minChanges: 3, threshold: 2,
+			weights: map[models.DeadCodeReason]float64{models.DeadCodeReasonSingleAuthor: 0.5, models.DeadCodeReasonOrphan: 1.5},
+		},
+		{
+			name: "built-ins per kind", path: "cmd/main.go", lang: "go", kind: functionNode,
+			rule: DefaultRuleName, inactiveDays: 120, minChanges: 2, threshold: 2,
+			weights: map[models.DeadCodeReason]float64{models.DeadCodeReasonUnreachable: 1},
+		},
+		{
+			name: "never", path: "migrations/001_init.sql", lang: "unknown", kind: fileNode,
+			rule: "migrations", never: true, inactiveDays: 120, minChanges: 3, threshold: 2,
+		},
+		{
+			name: "nested under a glob", path: "migrations/2024/002_users.sql", lang: "unknown", kind: fileNode,
+			rule: "migrations", never: true, inactiveDays: 120, minChanges: 3, threshold: 2,
+		},
+		{
+			name: "path and language must both match", path: "internal/legacy/old.go", lang: "go", kind: fileNode,
+			rule: "internal/legacy/**", inactiveDays: 90, minChanges: 3, threshold: 1,
+		},
+		{
+			name: "first match decides, no merging with later rules", path: "internal/legacy/old.py", lang: "python", kind: fileNode,
+			rule: "internal/legacy/**", inactiveDays: 120, minChanges: 10, threshold: 2,
+			weights: map[models.DeadCodeReason]float64{models.DeadCodeReasonFewChanges: 1},
+		},
+		{
+			name: "language only", path: "app/views.py", lang: "python", kind: functionNode,
+			rule: "languages: python", inactiveDays: 120, minChanges: 2, threshold: 2,
+			weights:  map[models.DeadCodeReason]float64{models.DeadCodeReasonFewChanges: 2, models.DeadCodeReasonInactive: 1},
+			exported: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := engine.Policy(tt.path, tt.lang, tt.kind)
+
+			if p.Rule != tt.rule || p.Never != tt.never {
+				t.Errorf("rule = %q (never %v), want %q (never %v)", p.Rule, p.Never, tt.rule, tt.never)
+			}
+			if p.InactiveDays != tt.inactiveDays || p.MinChanges != tt.minChanges || p.Threshold != tt.threshold {
+				t.Errorf("inactiveDays, minChanges, threshold = %d, %d, %v, want %d, %d, %v",
+					p.InactiveDays, p.MinChanges, p.Threshold, tt.inactiveDays, tt.minChanges, tt.threshold)
+			}
+			for reason, want := range tt.weights {
+				if got := p.Weights[reason]; got != want {
+					t.Errorf("weight %s = %v, want %v", reason, got, want)
+				}
+			}
+			if p.IncludeExported != tt.exported {
+				t.Errorf("includeExported = %v, want %v", p.IncludeExported, tt.exported)
+			}
+		})
+	}
+
+	// Policies are copies: a rule's weights must not leak into the built-ins
+	if builtinSettings[functionNode].Weights[models.DeadCodeReasonFewChanges] != 1 {
+		t.Errorf("built-in weights were modified")
+	}
+}
+
+func TestNewRuleEngineRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *models.RulesConfig
+		want string
+	}{
+		{
+			name: "unknown reason in defaults",
+			cfg: &models.RulesConfig{DeadCode: models.DeadCodeRules{Defaults: models.DeadCodeSettings{
+				Weights: map[models.DeadCodeReason]float64{"single_author": 1},
+			}}},
+			want: `defaults: unknown reason "single_author"`,
+		},
+		{
+			name: "unknown reason in a rule",
+			cfg: &models.RulesConfig{DeadCode: models.DeadCodeRules{Rules: []models.DeadCodeRule{{
+				Name:             "python",
+				DeadCodeSettings: models.DeadCodeSettings{Weights: map[models.DeadCodeReason]float64{"inactve": 1}},
+			}}}},
+			want: `rule "python": unknown reason "inactve"`,
+		},
+		{
+			name: "bad glob",
+			cfg: &models.RulesConfig{DeadCode: models.DeadCodeRules{Rules: []models.DeadCodeRule{{
+				Paths: []string{"src/[z-a]"},
+			}}}},
+			want: "invalid path",
+		},
+		{
+			name: "bad commit pattern",
+			cfg:  &models.RulesConfig{Commits: models.CommitRules{Patterns: map[models.CommitKind][]string{models.CommitFix: {"("}}}},
+			want: "commits",
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := NewRuleEngine(tt.cfg)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMergeRules(t *testing.T) {
+	repo := &models.RulesConfig{
+		DeadCode: models.DeadCodeRules{
+			Defaults: models.DeadCodeSettings{
+				InactiveDays: intPtr(200),
+				MinChanges:   intPtr(4),
+				Weights: map[models.DeadCodeReason]float64{
+					models.DeadCodeReasonInactive:     2,
+					models.DeadCodeReasonSingleAuthor: 0,
+				},
+			},
+			Rules: []models.DeadCodeRule{{Name: "repo"}},
+		},
+		Ownership: models.OwnershipRules{KnowledgeAtRiskDays: intPtr(60)},
+		Commits:   models.CommitRules{Patterns: map[models.CommitKind][]string{models.CommitFix: {"repo"}}},
+	}
+	request := &models.RulesConfig{
+		DeadCode: models.DeadCodeRules{
+			Defaults: models.DeadCodeSettings{
+				InactiveDays: intPtr(30),
+				Weights:      map[models.DeadCodeReason]float64{models.DeadCodeReasonSingleAuthor: 1},
+			},
+			Rules: []models.DeadCodeRule{{Name: "request"}},
+		},
+		Commits: models.CommitRules{Patterns: map[models.CommitKind][]string{models.CommitFix: {"request"}}},
+	}
+
+	merged := MergeRules(request, repo)
+	defaults := merged.DeadCode.Defaults
+
+	if got := *defaults.InactiveDays; got != 30 {
+		t.Errorf("inactiveDays = %d, want the request's 30", got)
+	}
+	if got := *defaults.MinChanges; got != 4 {
+		t.Errorf("minChanges = %d, want the repo's 4", got)
+	}
+	if defaults.Threshold != nil {
+		t.Errorf("threshold = %v, want unset", *defaults.Threshold)
+	}
+	wantWeights := map[models.DeadCodeReason]float64{models.DeadCodeReasonInactive: 2, models.DeadCodeReasonSingleAuthor: 1}
+	if len(defaults.Weights) != len(wantWeights) {
+		t.Errorf("weights = %v, want %v", defaults.Weights, wantWeights)
+	}
+	for reason, want := range wantWeights {
+		if defaults.Weights[reason] != want {
+			t.Errorf("weight %s = %v, want %v", reason, defaults.Weights[reason], want)
+		}
+	}
+	if repo.DeadCode.Defaults.Weights[models.DeadCodeReasonSingleAuthor] != 0 {
+		t.Errorf("merging modified the repo's weights")
+	}
+
+	if len(merged.DeadCode.Rules) != 2 || merged.DeadCode.Rules[0].Name != "request" || merged.DeadCode.Rules[1].Name != "repo" {
+		t.Errorf("rules = %v, want request rules before repo rules", merged.DeadCode.Rules)
+	}
+	if got := merged.Commits.Patterns[models.CommitFix]; strings.Join(got, ",") != "request,repo" {
+		t.Errorf("fix patterns = %v, want [request repo]", got)
+	}
+	if got := *merged.Ownership.KnowledgeAtRiskDays; got != 60 {
+		t.Errorf("knowledgeAtRiskDays = %d, want the repo's 60", got)
+	}
+
+	if MergeRules(nil, repo) != repo || MergeRules(request, nil) != request {
+		t.Errorf("merging with nothing should return the other side")
+	}
+}
+
+func TestLoadRulesFile(t *testing.T) {
+	dir := t.TempDir()
+	if cfg, err := LoadRulesFile(dir); cfg != nil || err != nil {
+		t.Fatalf("no file: got %v, %v, want nil, nil", cfg, err)
+	}
+
+	yaml := `deadCode:
+  defaults:
+    threshold: 3
+  rules:
+    - name: legacy
+      paths: ["legacy/**"]
+      inactiveDays: 30
+      weights:
+        single-author: 0.5
+`
+	if err := os.WriteFile(filepath.Join(dir, ".firesight.yml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadRulesFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewRuleEngine(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := engine.Policy("legacy/a.go", "go", fileNode)
+	if p.Rule != "legacy" || p.InactiveDays != 30 || p.Threshold != 3 || p.Weights[models.DeadCodeReasonSingleAuthor] != 0.5 {
+		t.Errorf("policy = %+v, want rule legacy, 30 days, threshold 3, single-author 0.5", p)
+	}
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// ValidateSeriesBucket checks a requested series bucket size ("" = no series)
+func ValidateSeriesBucket(bucket string) error {
+	switch models.SeriesBucket(bucket) {
+	case "", models.SeriesWeekly, models.SeriesMonthly:
+		return nil
+	default:
+		return fmt.Errorf("unknown series bucket %q (want %s or %s)", bucket, models.SeriesWeekly, models.SeriesMonthly)
+	}
+}
+
+// seriesBuilder turns "days ago" maps into bucketed time series over the
+// analysis window. Every node gets the same buckets, so folders can add
+// their children's series point by point.
+type seriesBuilder struct {
+	bucket models.SeriesBucket
+	today  time.Time // midnight of the analysis day
+	starts []time.Time
+}
+
+// newSeriesBuilder returns nil when no series was requested
+func newSeriesBuilder(bucket models.SeriesBucket, analyzedAt time.Time, windowDays int) *seriesBuilder {
+	if bucket == "" {
+		return nil
+	}
+	if windowDays <= 0 {
+		windowDays = 180
+	}
+
+	sb := &seriesBuilder{
+		bucket: bucket,
+		today:  time.Date(analyzedAt.Year(), analyzedAt.Month(), analyzedAt.Day(), 0, 0, 0, 0, analyzedAt.Location()),
+	}
+
+	first := sb.today.AddDate(0, 0, -windowDays)
+	switch bucket {
+	case models.SeriesMonthly:
+		// Calendar months, the current one included
+		month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, first.Location())
+		for !month.After(sb.today) {
+			sb.starts = append(sb.starts, month)
+			month = month.AddDate(0, 1, 0)
+		}
+	default:
+		// Weeks ending today
+		weeks := (windowDays + 6) / 7
+		for i := weeks - 1; i >= 0; i-- {
+			sb.starts = append(sb.starts, sb.today.AddDate(0, 0, -7*i-6))
+		}
+	}
+
+	return sb
+}
+
+// index of the bucket holding a change made dayOffset days ago, -1 outside the window
+func (sb *seriesBuilder) index(dayOffset int) int {
+	last := len(sb.starts) - 1
+
+	if sb.bucket == models.SeriesMonthly {
+		day := sb.today.AddDate(0, 0, -dayOffset)
+		for i := last; i >= 0; i-- {
+			if !day.Before(sb.starts[i]) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	i := last - dayOffset/7
+	if i < 0 {
+		return -1
+	}
+	return i
+}
+
+// build buckets changes and churn (added/removed may be nil), oldest first
+func (sb *seriesBuilder) build(changes, added, removed map[int]int) []models.SeriesPoint {
+	if sb == nil {
+		return nil
+	}
+
+	points := sb.empty()
+	for dayOffset, count := range changes {
+		if i := sb.index(dayOffset); i >= 0 {
+			points[i].Changes += count
+		}
+	}
+	for dayOffset, lines := range added {
+		if i := sb.index(dayOffset); i >= 0 {
+			points[i].LinesAdded += lines
+		}
+	}
+	for dayOffset, lines := range removed {
+		if i := sb.index(dayOffset); i >= 0 {
+			points[i].LinesRemoved += lines
+		}
+	}
+
+	return points
+}
+
+func (sb *seriesBuilder) empty() []models.SeriesPoint {
+	points := make([]models.SeriesPoint, len(sb.starts))
+	for i, start := range sb.starts {
+		points[i].Start = start
+	}
+	return points
+}
+
+// mergeSeries adds src into dst point by point (same builder, same
+// buckets), starting from zero when dst is nil
+func mergeSeries(dst, src []models.SeriesPoint) []models.SeriesPoint {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = make([]models.SeriesPoint, len(src))
+		for i := range src {
+			dst[i].Start = src[i].Start
+		}
+	}
+
+	for i := range src {
+		if i >= len(dst) {
+			break
+		}
+		dst[i].Changes += src[i].Changes
+		dst[i].LinesAdded += src[i].LinesAdded
+		dst[i].LinesRemoved += src[i].LinesRemoved
+	}
+	return dst
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// Sunday afternoon; buckets start at midnight
+var seriesAnalyzedAt = time.Date(2026, time.March, 15, 15, 30, 0, 0, time.UTC)
+
+func seriesDay(month time.Month, day int) time.Time {
+	return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSeriesBuckets(t *testing.T) {
+	tests := []struct {
+		name       string
+		bucket     models.SeriesBucket
+		windowDays int
+		starts     []time.Time
+		index      map[int]int // days ago -> bucket, -1 outside the window
+	}{
+		{
+			name: "weeks ending today", bucket: models.SeriesWeekly, windowDays: 28,
+			starts: []time.Time{seriesDay(2, 16), seriesDay(2, 23), seriesDay(3, 2), seriesDay(3, 9)},
+			index:  map[int]int{0: 3, 6: 3, 7: 2, 13: 2, 14: 1, 27: 0, 28: -1},
+		},
+		{
+			// a partial week rounds up to a whole one
+			name: "weeks, window not a multiple of 7", bucket: models.SeriesWeekly, windowDays: 30,
+			starts: []time.Time{seriesDay(2, 9), seriesDay(2, 16), seriesDay(2, 23), seriesDay(3, 2), seriesDay(3, 9)},
+			index:  map[int]int{0: 4, 30: 0, 34: 0, 35: -1},
+		},
+		{
+			name: "calendar months", bucket: models.SeriesMonthly, windowDays: 30,
+			starts: []time.Time{seriesDay(2, 1), seriesDay(3, 1)},
+			index:  map[int]int{0: 1, 14: 1, 15: 0, 30: 0, 42: 0, 43: -1},
+		},
+		{
+			name: "months, default window", bucket: models.SeriesMonthly,
+			starts: []time.Time{seriesDay(9, 1).AddDate(-1, 0, 0), seriesDay(10, 1).AddDate(-1, 0, 0),
+				seriesDay(11, 1).AddDate(-1, 0, 0), seriesDay(12, 1).AddDate(-1, 0, 0),
+				seriesDay(1, 1), seriesDay(2, 1), seriesDay(3, 1)},
+			index: map[int]int{0: 6, 180: 0, 200: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := newSeriesBuilder(tt.bucket, seriesAnalyzedAt, tt.windowDays)
+			if len(sb.starts) != len(tt.starts) {
+				t.Fatalf("starts = %v, want %v", sb.starts, tt.starts)
+			}
+			for i := range sb.starts {
+				if !sb.starts[i].Equal(tt.starts[i]) {
+					t.Errorf("start %d = %v, want %v", i, sb.starts[i], tt.starts[i])
+				}
+			}
+			for days, want := range tt.index {
+				if got := sb.index(days); got != want {
+					t.Errorf("index(%d) = %d, want %d", days, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSeriesBuild(t *testing.T) {
+	if sb := newSeriesBuilder("", seriesAnalyzedAt, 28); sb != nil || sb.build(map[int]int{0: 1}, nil, nil) != nil {
+		t.Errorf("no bucket should build no series")
+	}
+
+	sb := newSeriesBuilder(models.SeriesWeekly, seriesAnalyzedAt, 14)
+	points := sb.build(map[int]int{0: 2, 6: 1, 7: 4, 50: 9}, map[int]int{0: 10, 8: 3}, nil)
+
+	want := []models.SeriesPoint{
+		{Start: seriesDay(3, 2), Changes: 4, LinesAdded: 3},
+		{Start: seriesDay(3, 9), Changes: 3, LinesAdded: 10},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("points = %+v, want %+v", points, want)
+	}
+	for i := range want {
+		if !points[i].Start.Equal(want[i].Start) || points[i].Changes != want[i].Changes ||
+			points[i].LinesAdded != want[i].LinesAdded || points[i].LinesRemoved != want[i].LinesRemoved {
+			t.Errorf("point %d = %+v, want %+v", i, points[i], want[i])
+		}
+	}
+
+	// Folders add their children point by point
+	merged := mergeSeries(nil, points)
+	merged = mergeSeries(merged, sb.build(nil, nil, map[int]int{1: 5}))
+	merged = mergeSeries(merged, nil)
+	if merged[0].Changes != 4 || merged[1].Changes != 3 || merged[1].LinesRemoved != 5 {
+		t.Errorf("merged = %+v", merged)
+	}
+	if points[1].LinesRemoved != 0 {
+		t.Errorf("merging changed the source series")
+	}
+}
+
+func TestValidateSeriesBucket(t *testing.T) {
+	for bucket, ok := range map[string]bool{"": true, "week": true, "month": true, "day": false, "weekly": false} {
+		if err := ValidateSeriesBucket(bucket); (err == nil) != ok {
+			t.Errorf("ValidateSeriesBucket(%q) = %v", bucket, err)
+		}
+	}
+}
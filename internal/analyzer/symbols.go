@@ -0,0 +1,366 @@
+package analyzer
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+	"github.com/richd0tcom/fire-sight/internal/parser"
+)
+
+// LEARNING MOMENT: Textual Reference Counting
+//
+// Without a type checker for JS/TS and Python we fall back to names:
+//
+// 1. Parse every file with the usual parsers -> definitions + line ranges
+// 2. Tokenize every file -> identifier occurrences per line
+//    (comments are dropped, strings are kept: Vue templates and
+//    getattr(obj, "name") reference functions through strings)
+// 3. references(fn) = occurrences of fn's name anywhere in the repo,
+//    minus the ones inside fn's own definition (recursion, the name itself)
+//    minus import/export statements (re-exporting is not using)
+// 4. Default exports are referenced by module, not by name:
+//    import Button from './Button' counts as one reference of Button.tsx's default
+//
+// Over-counting is the safe direction here: a common name like "get" will
+// look referenced even when it isn't, but nothing that is used gets flagged.
+
+var (
+	identifierPattern = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
+	jsImportStart   = regexp.MustCompile(`^\s*import\b`)
+	jsRequireAssign = regexp.MustCompile(`^\s*(?:const|let|var)\s+[^=]+=\s*require\s*\(`)
+	jsExportLine    = regexp.MustCompile(`^\s*(?:export\s*\{|export\s+\*|export\s+default\s+[\w$]+\s*;?\s*$|module\.exports\s*=|exports\.[\w$]+\s*=)`)
+	jsDefaultExport = regexp.MustCompile(`^\s*export\s+default\s+(?:async\s+)?(?:\bfunction\b\s*\*?\s*)?([\w$]+)`)
+	jsDefaultImport = regexp.MustCompile(`^\s*import\s+([\w$]+)\s*(?:,|from\b)`)
+	jsModuleSource  = regexp.MustCompile(`(?:\bfrom\s*|\brequire\s*\(\s*|\bimport\s*\(?\s*)['"]([^'"]+)['"]`)
+
+	pyImportStart = regexp.MustCompile(`^\s*(?:from\s+\S+\s+import\b|import\s+)`)
+	pyExportStart = regexp.MustCompile(`^\s*__all__\s*[+]?=`)
+)
+
+// Names the runtime or a framework calls for us - never referenced by name
+var implicitlyCalled = map[string]bool{
+	// JS classes / React / Vue / Svelte
+	"constructor": true, "render": true,
+	"componentDidMount": true, "componentDidUpdate": true, "componentWillUnmount": true,
+	"shouldComponentUpdate": true, "getDerivedStateFromProps": true, "componentDidCatch": true,
+	"data": true, "setup": true, "beforeCreate": true, "created": true,
+	"beforeMount": true, "mounted": true, "beforeUpdate": true, "updated": true,
+	"beforeUnmount": true, "unmounted": true, "beforeDestroy": true, "destroyed": true,
+	"activated": true, "deactivated": true,
+
+	// Python test runners
+	"setUp": true, "tearDown": true, "setUpClass": true, "tearDownClass": true,
+	"setUpModule": true, "tearDownModule": true,
+}
+
+// SymbolIndex counts textual references to JS/TS and Python definitions
+type SymbolIndex struct {
+	// file path -> unit name -> references from outside the definition
+	references map[string]map[string]int
+}
+
+// indexedFile is everything the index needs to know about one file
+type indexedFile struct {
+	path        string
+	lang        parser.Language
+	definitions []*parser.Function
+	occurrences map[string][]int // identifier -> lines it appears on
+	imports     []moduleImport
+	defaultName string // name of the default export, if any
+
+	pendingDefault bool // default specifier seen, module source not yet
+}
+
+type moduleImport struct {
+	source        string
+	importDefault bool
+}
+
+// BuildSymbolIndex indexes every JS/TS/Python file among paths
+func BuildSymbolIndex(repoPath string, paths []string, detector *parser.LanguageDetector) *SymbolIndex {
+	files := []*indexedFile{}
+
+	for _, p := range paths {
+		content, err := os.ReadFile(filepath.Join(repoPath, p))
+		if err != nil {
+			continue
+		}
+
+		detection := detector.Detect(p, content)
+		if !isIndexedLanguage(detection.Language) {
+			continue
+		}
+
+		definitions, err := parser.GetParser(detection.Language).Parse(bytes.NewReader(content))
+		if err != nil {
+			continue
+		}
+
+		file := &indexedFile{
+			path:        p,
+			lang:        detection.Language,
+			definitions: definitions,
+			occurrences: make(map[string][]int),
+		}
+		file.scan(string(content))
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	// Total occurrences per name across the whole repo
+	totals := make(map[string]int)
+	for _, file := range files {
+		for name, lines := range file.occurrences {
+			totals[name] += len(lines)
+		}
+	}
+
+	index := &SymbolIndex{references: make(map[string]map[string]int)}
+
+	for _, file := range files {
+		units := make(map[string]int)
+
+		for _, fn := range file.definitions {
+			name := shortName(fn.Name)
+			if _, done := units[fn.Name]; done || fn.Type == parser.TypeCell {
+				continue
+			}
+
+			refs := totals[name] - file.ownOccurrences(name)
+			if name == file.defaultName {
+				refs += countDefaultImports(file, files)
+			}
+			if implicitlyCalled[name] || isPythonImplicit(file.lang, name) {
+				refs++ // the runtime is a caller too
+			}
+
+			units[fn.Name] = refs
+		}
+
+		index.references[file.path] = units
+	}
+
+	return index
+}
+
+// References returns the reference count of a parser unit.
+// ok is false when the file wasn't indexed.
+func (si *SymbolIndex) References(filePath, unitName string) (int, bool) {
+	if si == nil {
+		return 0, false
+	}
+
+	units, indexed := si.references[filePath]
+	if !indexed {
+		return 0, false
+	}
+
+	refs, ok := units[unitName]
+	return refs, ok
+}
+
+// applyReferences copies reference counts onto a file's function stats
+func applyReferences(analysis *models.FileAnalysis, index *SymbolIndex) {
+	for _, fn := range analysis.Functions {
+		if fn.Type == string(parser.TypeCell) {
+			continue // cells aren't referenced by name
+		}
+		if refs, ok := index.References(analysis.Path, fn.Name); ok {
+			fn.References = &refs
+		}
+	}
+}
+
+// scan records identifier occurrences, imports and exports of a file
+func (f *indexedFile) scan(content string) {
+	python := f.lang == parser.LangPython || f.lang == parser.LangJupyter
+
+	inBlockComment := false
+	inStatement := false // multi-line import/export still open
+	closer := ""
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNum := i + 1
+
+		if python {
+			line = stripPythonComment(line)
+		} else {
+			line, inBlockComment = stripJSComment(line, inBlockComment)
+		}
+
+		if inStatement {
+			f.recordModuleSources(line)
+			if strings.Contains(line, closer) {
+				inStatement = false
+			}
+			continue
+		}
+
+		if python {
+			if pyImportStart.MatchString(line) || pyExportStart.MatchString(line) {
+				// from x import (\n a,\n b\n)  /  __all__ = [\n "a",\n]
+				if open, close := statementBrackets(line); open != "" && !strings.Contains(line, close) {
+					inStatement, closer = true, close
+				}
+				continue
+			}
+		} else {
+			if matches := jsDefaultExport.FindStringSubmatch(line); matches != nil {
+				f.defaultName = matches[1]
+			}
+
+			if jsImportStart.MatchString(line) || jsRequireAssign.MatchString(line) {
+				f.pendingDefault = jsDefaultImport.MatchString(line) || jsRequireAssign.MatchString(line)
+				f.recordModuleSources(line)
+				// import {\n a,\n b\n} from './x'
+				if jsImportStart.MatchString(line) && !jsModuleSource.MatchString(line) {
+					inStatement, closer = true, "from"
+				}
+				continue
+			}
+
+			if jsExportLine.MatchString(line) {
+				if strings.Contains(line, "{") && !strings.Contains(line, "}") {
+					inStatement, closer = true, "}"
+				}
+				continue
+			}
+		}
+
+		for _, ident := range identifierPattern.FindAllString(line, -1) {
+			f.occurrences[ident] = append(f.occurrences[ident], lineNum)
+		}
+	}
+}
+
+func (f *indexedFile) recordModuleSources(line string) {
+	for _, matches := range jsModuleSource.FindAllStringSubmatch(line, -1) {
+		f.imports = append(f.imports, moduleImport{source: matches[1], importDefault: f.pendingDefault})
+		f.pendingDefault = false
+	}
+}
+
+// ownOccurrences counts a name's occurrences inside its own definitions
+func (f *indexedFile) ownOccurrences(name string) int {
+	own := 0
+
+	for _, line := range f.occurrences[name] {
+		for _, fn := range f.definitions {
+			if shortName(fn.Name) == name && line >= fn.LineStart && line <= fn.LineEnd {
+				own++
+				break
+			}
+		}
+	}
+
+	return own
+}
+
+// countDefaultImports counts files importing target's module by default
+func countDefaultImports(target *indexedFile, files []*indexedFile) int {
+	stem := strings.TrimSuffix(path.Base(target.path), path.Ext(target.path))
+	dir := path.Base(path.Dir(target.path))
+
+	count := 0
+	for _, file := range files {
+		if file == target {
+			continue
+		}
+		for _, imp := range file.imports {
+			if !imp.importDefault {
+				continue
+			}
+
+			source := strings.TrimSuffix(imp.source, "/")
+			base := strings.TrimSuffix(path.Base(source), path.Ext(source))
+			if base == stem || (stem == "index" && base == dir) {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+func isIndexedLanguage(lang parser.Language) bool {
+	switch lang {
+	case parser.LangJavaScript, parser.LangTypeScript, parser.LangPython,
+		parser.LangVue, parser.LangSvelte, parser.LangAstro, parser.LangJupyter:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPythonImplicit: dunder methods and pytest/unittest tests
+func isPythonImplicit(lang parser.Language, name string) bool {
+	if lang != parser.LangPython && lang != parser.LangJupyter {
+		return false
+	}
+	return (strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")) ||
+		strings.HasPrefix(name, "test")
+}
+
+// shortName drops qualifiers: "(*User).GetName" -> "GetName", "outer.inner" -> "inner"
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func statementBrackets(line string) (open, close string) {
+	switch {
+	case strings.Contains(line, "("):
+		return "(", ")"
+	case strings.Contains(line, "["):
+		return "[", "]"
+	default:
+		return "", ""
+	}
+}
+
+func stripPythonComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// stripJSComment removes // and /* */ comments; inBlock carries state across lines
+func stripJSComment(line string, inBlock bool) (string, bool) {
+	var b strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		if inBlock {
+			if strings.HasPrefix(line[i:], "*/") {
+				inBlock = false
+				i++
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line[i:], "/*") {
+			inBlock = true
+			i++
+			continue
+		}
+		if strings.HasPrefix(line[i:], "//") && (i == 0 || line[i-1] != ':') {
+			// "://" is most likely a URL inside a string
+			break
+		}
+
+		b.WriteByte(line[i])
+	}
+
+	return b.String(), inBlock
+}
@@ -10,22 +10,59 @@ import (
 )
 
 
+// TreeBuilder turns analysis results into file trees. Everything a build
+// needs lives in a treeBuild of its own, so one builder can serve
+// concurrent requests and no tree shares nodes with another.
 type TreeBuilder struct {
-	// Maps path -> node for O(1) lookups during construction
-	nodeCache map[string]*models.FileNode
 	hc *HeatCalculator
 }
 
 func NewTreeBuilder(hc *HeatCalculator) *TreeBuilder {
 	return &TreeBuilder{
-		nodeCache: make(map[string]*models.FileNode),
 		hc: hc,
 	}
 }
 
+// treeBuild is the state of one BuildTree call
+type treeBuild struct {
+	hc *HeatCalculator
+
+	// Maps path -> node for O(1) lookups during construction
+	nodeCache map[string]*models.FileNode
+
+	rules      *RuleEngine
+	heatModel  HeatModel
+	windowDays int
+	sortBy     string
+	ownership  *ownershipScorer
+}
+
+// BuildTree is safe for concurrent use; it only reads analysisResult
 func (tb *TreeBuilder) BuildTree(analysisResult *models.AnalysisResult) *models.FileNode {
 
-	heatScores:= tb.hc.CalculateHeatScores(analysisResult)
+	rules, err := NewRuleEngine(analysisResult.Rules)
+	if err != nil {
+		// Rules are validated before analysis; fall back to built-in thresholds
+		rules, _ = NewRuleEngine(nil)
+	}
+
+	heatModel, err := NewHeatModel(analysisResult.HeatModel)
+	if err != nil {
+		// Validated before analysis too; fall back to the default decay
+		heatModel, _ = NewHeatModel(nil)
+	}
+
+	b := &treeBuild{
+		hc:         tb.hc,
+		nodeCache:  make(map[string]*models.FileNode),
+		rules:      rules,
+		heatModel:  heatModel,
+		windowDays: analysisResult.TimeRangeDays,
+		sortBy:     analysisResult.SortBy,
+		ownership:  newOwnershipScorer(analysisResult),
+	}
+
+	heatScores:= tb.hc.CalculateHeatScores(analysisResult, heatModel)
 	fileStats:= analysisResult.FileStats
 	fileFxnAnlysis:= analysisResult.FileFunctionAnalyses
 	
@@ -37,26 +74,40 @@ func (tb *TreeBuilder) BuildTree(analysisResult *models.AnalysisResult) *models.
 		Type:     "folder",
 		Children: []*models.FileNode{},
 	}
-	tb.nodeCache[""] = root
+	b.nodeCache[""] = root
+
+	series := newSeriesBuilder(analysisResult.Series, analysisResult.AnalyzedAt, analysisResult.TimeRangeDays)
 
 	// Process each file
+	files := make([]*models.FileNode, 0, len(heatScores))
 	for _, score := range heatScores {
-		tb.addFileToTree(root, score, fileStats[score.Path], fileFxnAnlysis[score.Path])
+		file := b.addFileToTree(root, score, fileStats[score.Path], fileFxnAnlysis[score.Path])
+		file.Ownership = b.ownership.score(file.Authors)
+		file.Series = series.build(fileStats[score.Path].ChangesByDay, fileStats[score.Path].AddedByDay, fileStats[score.Path].RemovedByDay)
+		if anlysis := fileFxnAnlysis[score.Path]; anlysis != nil && len(anlysis.Functions) == len(file.Functions) {
+			for i, fn := range file.Functions {
+				fn.Series = series.build(anlysis.Functions[i].ChangesByDay, nil, nil)
+			}
+		}
+		files = append(files, file)
 	}
 
+	// Hotspots are ranked across the whole repo
+	rankHotspots(files, heatModel)
+
 	// Calculate aggregated stats for folders (bottom-up)
-	tb.aggregateFolderStats(root)
+	b.aggregateFolderStats(root)
 
 	return root
 }
 
 // addFileToTree inserts a file into the tree, creating parent folders as needed
-func (tb *TreeBuilder) addFileToTree(
+func (b *treeBuild) addFileToTree(
 	root *models.FileNode, 
 	score models.HeatScore, 
 	stats *models.FileChangeStats,
 	anlysis *models.FileAnalysis,
-	) {
+	) *models.FileNode {
 	// Split path into parts: "src/components/Button.tsx" -> ["src", "components", "Button.tsx"]
 	parts := strings.Split(score.Path, "/")
 	
@@ -79,7 +130,7 @@ func (tb *TreeBuilder) addFileToTree(
 		isFile := i == len(parts)-1
 
 		// Check if node already exists (O(1) lookup)
-		node, exists := tb.nodeCache[currentPath]
+		node, exists := b.nodeCache[currentPath]
 		
 		if !exists {
 			// Create new node
@@ -87,20 +138,50 @@ func (tb *TreeBuilder) addFileToTree(
 				ID:       generateNodeID(currentPath),
 				Name:     part,
 				Path:     currentPath,
-				Type:     tb.getNodeType(isFile),
+				Type:     b.getNodeType(isFile),
 				Children: []*models.FileNode{},
 			}
 
 			// Add file-specific data
 			if isFile {
-				node.Extension = tb.getExtension(part)
+				node.Extension = b.getExtension(part)
+				if anlysis != nil {
+					node.Language = anlysis.Language
+					node.Vendored = anlysis.Vendored
+					node.Generated = anlysis.Generated
+					node.Coverage = anlysis.Coverage
+					node.RuntimeShare = anlysis.RuntimeShare
+					node.Suppression = anlysis.Suppression
+					node.Orphan = anlysis.Orphan
+					node.Complexity = anlysis.Complexity
+				}
+				node.CommitKinds = stats.CommitKinds
+				node.Authors = stats.UniqueAuthors
 				node.Size = 0 // TODO: Add in Milestone 2 when we parse files
 				node.LinesOfCode = 0
 				node.LastModified = stats.LastModified
+
+				filePolicy := b.rules.Policy(score.Path, node.Language, fileNode)
+				node.IsDeadCode, node.DeadCodeReasons = b.hc.isLikelyDeadCode(stats, anlysis, time.Now(), filePolicy)
+				node.DeadCodeRule = filePolicy.Rule
+
+				authors := len(stats.UniqueAuthors)
+				node.DeadCodeConfidence, node.DeadCodeSignals = b.hc.deadCodeConfidence(confidenceInput{
+					daysSinceEdit: score.DaysSinceEdit,
+					changes:       stats.TotalChanges,
+					authors:       &authors,
+					orphan:        node.Orphan,
+					coverage:      node.Coverage,
+					runtimeShare:  node.RuntimeShare,
+					suppressed:    node.Suppression != nil,
+				}, filePolicy)
 		
 				node.HeatScore = &score
 				if anlysis != nil && len(anlysis.Functions) > 0 {
-					node.Functions = tb.hc.CalculateFunctionHeatScore(anlysis.Functions)
+					node.Functions = b.hc.CalculateFunctionHeatScore(anlysis.Functions, b.rules.Policy(score.Path, node.Language, functionNode), b.heatModel, b.windowDays)
+					if stats.NoChangesInWindow {
+						coolFunctions(node.Functions)
+					}
 				} else {
 					node.Functions = []*models.FunctionNode{}
 				}
@@ -110,22 +191,24 @@ func (tb *TreeBuilder) addFileToTree(
 			currentNode.Children = append(currentNode.Children, node)
 			
 			// Cache for O(1) future lookups
-			tb.nodeCache[currentPath] = node
+			b.nodeCache[currentPath] = node
 		}
 
 		currentNode = node
 	}
+
+	return currentNode
 }
 
 // aggregateFolderStats calculates folder metrics from children (recursive, bottom-up)
-func (tb *TreeBuilder) aggregateFolderStats(node *models.FileNode) {
+func (b *treeBuild) aggregateFolderStats(node *models.FileNode) {
 	if node.Type == "file" {
 		return // Base case: files already have stats
 	}
 
 	// Recursively process children first
 	for _, child := range node.Children {
-		tb.aggregateFolderStats(child)
+		b.aggregateFolderStats(child)
 	}
 
 	// Aggregate from children
@@ -134,17 +217,50 @@ func (tb *TreeBuilder) aggregateFolderStats(node *models.FileNode) {
 		totalSize       int64
 		totalLines      int
 		totalChanges    int
+		recentChanges   int
+		olderChanges    int
+		series          []models.SeriesPoint
+		fixCommits      int
+		weightedDefect  float64
+		commitKinds     map[models.CommitKind]int
+		authors         map[string]int
+		atRiskFiles     int
 		latestModified  time.Time
 		weightedHeat    float64
 		totalHeatWeight int
+		deadFiles       int
+		deadFunctions   int
 	)
 
 	for _, child := range node.Children {
 		if child.Type == "file" {
+			// .firesightignore: listed, but not part of the folder's numbers
+			if child.Suppression.Ignored() {
+				continue
+			}
+
 			totalFiles++
 			totalSize += child.Size
 			totalLines += child.LinesOfCode
 			totalChanges += child.HeatScore.TotalFileChanges
+			recentChanges += child.HeatScore.RecentChanges
+			olderChanges += child.HeatScore.OlderChanges
+			series = mergeSeries(series, child.Series)
+			commitKinds = addCommitKinds(commitKinds, child.CommitKinds)
+			fixCommits += child.HeatScore.FixCommits
+			authors = addAuthors(authors, child.Authors)
+			if child.Ownership != nil && child.Ownership.KnowledgeAtRisk {
+				atRiskFiles++
+			}
+
+			if child.IsDeadCode {
+				deadFiles++
+			}
+			for _, fn := range child.Functions {
+				if fn.IsDeadCode {
+					deadFunctions++
+				}
+			}
 			
 			// Track latest modification
 			if child.LastModified.After(latestModified) {
@@ -158,6 +274,7 @@ func (tb *TreeBuilder) aggregateFolderStats(node *models.FileNode) {
 				weight = 1 
 			}
 			weightedHeat += child.HeatScore.Score * float64(weight)
+			weightedDefect += child.HeatScore.DefectHeat * float64(weight)
 			totalHeatWeight += weight
 		} else {
 			// Folder - aggregate its aggregated stats
@@ -171,9 +288,20 @@ func (tb *TreeBuilder) aggregateFolderStats(node *models.FileNode) {
 				}
 			}
 			totalFiles += child.FileCount
+			deadFiles += child.DeadFileCount
+			deadFunctions += child.DeadFunctionCount
 			totalSize += child.Size
 			totalLines += child.LinesOfCode
 			totalChanges += child.HeatScore.TotalFileChanges
+			recentChanges += child.HeatScore.RecentChanges
+			olderChanges += child.HeatScore.OlderChanges
+			series = mergeSeries(series, child.Series)
+			commitKinds = addCommitKinds(commitKinds, child.CommitKinds)
+			fixCommits += child.HeatScore.FixCommits
+			authors = addAuthors(authors, child.Authors)
+			if child.Ownership != nil {
+				atRiskFiles += child.Ownership.AtRiskFiles
+			}
 			
 			if child.LastModified.After(latestModified) {
 				latestModified = child.LastModified
@@ -185,12 +313,15 @@ func (tb *TreeBuilder) aggregateFolderStats(node *models.FileNode) {
 			}
 			
 			weightedHeat += child.HeatScore.Score * float64(weight)
+			weightedDefect += child.HeatScore.DefectHeat * float64(weight)
 			totalHeatWeight += weight
 		}
 	}
 
 	// Set aggregated values
 	node.FileCount = totalFiles
+	node.DeadFileCount = deadFiles
+	node.DeadFunctionCount = deadFunctions
 	node.Size = totalSize
 	node.LinesOfCode = totalLines
 	node.LastModified = latestModified
@@ -206,18 +337,52 @@ func (tb *TreeBuilder) aggregateFolderStats(node *models.FileNode) {
 	}
 
 	node.HeatScore.TotalFileChanges = totalChanges
+	node.HeatScore.RecentChanges = recentChanges
+	node.HeatScore.OlderChanges = olderChanges
+	node.HeatScore.Trend = classifyTrend(recentChanges, olderChanges)
+	node.Series = series
+	node.CommitKinds = commitKinds
+	node.HeatScore.FixCommits = fixCommits
+	node.Authors = authors
+	node.Ownership = b.ownership.score(authors)
+	if node.Ownership != nil {
+		node.Ownership.AtRiskFiles = atRiskFiles
+	}
 	
 	// Calculate weighted average heat score
 	if totalHeatWeight > 0 {
 		node.HeatScore.Score = weightedHeat / float64(totalHeatWeight)
+		node.HeatScore.DefectHeat = weightedDefect / float64(totalHeatWeight)
 	}
 
-	// Sort children: folders first (alphabetically), then files (by heat score desc)
-	tb.sortChildren(node)
+	// Sort children: folders first (alphabetically), then files (by heat score or hotspot rank)
+	b.sortChildren(node)
+}
+
+// coolFunctions zeroes the scores of an untouched file's functions: with
+// nothing changed, the within-file scale would rate them on nothing
+func coolFunctions(functions []*models.FunctionNode) {
+	for _, fn := range functions {
+		fn.HeatScore.Score = 0
+		fn.HeatScore.NoChangesInWindow = true
+		fn.HotspotScore = 0
+	}
+}
+
+// addCommitKinds sums commit counts by kind into dst (allocated on first use)
+func addCommitKinds(dst, src map[models.CommitKind]int) map[models.CommitKind]int {
+	for kind, count := range src {
+		if dst == nil {
+			dst = make(map[models.CommitKind]int)
+		}
+		dst[kind] += count
+	}
+	return dst
 }
 
 // sortChildren orders children for optimal UI display
-func (tb *TreeBuilder) sortChildren(node *models.FileNode) {
+func (b *treeBuild) sortChildren(node *models.FileNode) {
+	sortBy := b.sortBy
 	sort.SliceStable(node.Children, func(i, j int) bool {
 		a, b := node.Children[i], node.Children[j]
 
@@ -233,6 +398,12 @@ func (tb *TreeBuilder) sortChildren(node *models.FileNode) {
 		if a.Type == "folder" {
 			// Folders: alphabetical
 			return a.Name < b.Name
+		} else if sortBy == models.SortByHotspot {
+			// Files: by hotspot rank (unranked last)
+			if a.HotspotRank == 0 || b.HotspotRank == 0 {
+				return a.HotspotRank > b.HotspotRank
+			}
+			return a.HotspotRank < b.HotspotRank
 		} else {
 			// Files: by heat score (hottest first)
 			return a.HeatScore.Score > b.HeatScore.Score
@@ -241,14 +412,14 @@ func (tb *TreeBuilder) sortChildren(node *models.FileNode) {
 }
 
 // Helper functions
-func (tb *TreeBuilder) getNodeType(isFile bool) models.FileNodeType {
+func (b *treeBuild) getNodeType(isFile bool) models.FileNodeType {
 	if isFile {
 		return "file"
 	}
 	return "folder"
 }
 
-func (tb *TreeBuilder) getExtension(filename string) string {
+func (b *treeBuild) getExtension(filename string) string {
 	ext := filepath.Ext(filename)
 	if ext != "" {
 		return ext[1:] // Remove leading dot
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// testResult fakes an analysis of repo n: files under a shared "src"
+// folder plus a folder of their own, so trees from different repos
+// overlap in their paths
+func testResult(n int) *models.AnalysisResult {
+	now := time.Now()
+	result := &models.AnalysisResult{
+		RepoURL:              fmt.Sprintf("https://example.com/repo-%d", n),
+		AnalyzedAt:           now,
+		TimeRangeDays:        180,
+		FileStats:            make(map[string]*models.FileChangeStats),
+		FileFunctionAnalyses: make(map[string]*models.FileAnalysis),
+		AuthorLastCommit:     map[string]time.Time{"alice": now, "bob": now.AddDate(0, 0, -120)},
+		Series:               models.SeriesWeekly,
+	}
+
+	paths := []string{
+		fmt.Sprintf("src/repo%d.go", n),
+		fmt.Sprintf("src/pkg%d/util.go", n),
+		"src/shared/common.go",
+		"README.md",
+	}
+	for i, path := range paths {
+		changes := i + n%3 + 1
+		result.FileStats[path] = &models.FileChangeStats{
+			FilePath:      path,
+			TotalChanges:  changes,
+			LastModified:  now.AddDate(0, 0, -i),
+			FirstSeen:     now.AddDate(0, 0, -30),
+			ChangesByDay:  map[int]int{i: changes},
+			CommitKinds:   map[models.CommitKind]int{models.CommitFix: 1},
+			FixCommits:    1,
+			FixesByDay:    map[int]int{i: 1},
+			UniqueAuthors: map[string]int{"alice": changes, "bob": 1},
+		}
+		result.FileFunctionAnalyses[path] = &models.FileAnalysis{
+			Path:     path,
+			Language: "go",
+			Functions: []*models.FunctionStats{{
+				Name:         "run",
+				Type:         "function",
+				LineStart:    1,
+				LineEnd:      10,
+				TotalChanges: changes,
+				LastModified: now.AddDate(0, 0, -i),
+				ChangesByDay: map[int]int{i: changes},
+				Complexity:   &models.Complexity{Cyclomatic: 3, MaxNesting: 1, Lines: 10},
+			}},
+		}
+	}
+
+	return result
+}
+
+// treeFiles lists the file paths in a tree, sorted
+func treeFiles(t *testing.T, root *models.FileNode) []string {
+	t.Helper()
+
+	files := []string{}
+	var walk func(node *models.FileNode)
+	walk = func(node *models.FileNode) {
+		if node.Type == models.FileNodeTypeFile {
+			files = append(files, node.Path)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	sort.Strings(files)
+	return files
+}
+
+func wantFiles(result *models.AnalysisResult) []string {
+	files := make([]string, 0, len(result.FileStats))
+	for path := range result.FileStats {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestBuildTreeDoesNotReuseNodes(t *testing.T) {
+	tb := NewTreeBuilder(NewHeatCalculator())
+
+	first := tb.BuildTree(testResult(1))
+	second := tb.BuildTree(testResult(2))
+
+	if got, want := treeFiles(t, second), wantFiles(testResult(2)); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("second tree files = %v, want %v", got, want)
+	}
+	if got, want := treeFiles(t, first), wantFiles(testResult(1)); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("first tree changed after the second build: files = %v, want %v", got, want)
+	}
+	if second.FileCount != len(testResult(2).FileStats) {
+		t.Errorf("second root fileCount = %d, want %d", second.FileCount, len(testResult(2).FileStats))
+	}
+}
+
+func TestBuildTreeConcurrent(t *testing.T) {
+	tb := NewTreeBuilder(NewHeatCalculator())
+
+	for n := 0; n < 32; n++ {
+		t.Run(fmt.Sprintf("repo-%d", n), func(t *testing.T) {
+			t.Parallel()
+
+			result := testResult(n)
+			root := tb.BuildTree(result)
+
+			if got, want := treeFiles(t, root), wantFiles(result); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("files = %v, want %v", got, want)
+			}
+			if root.FileCount != len(result.FileStats) {
+				t.Errorf("root fileCount = %d, want %d", root.FileCount, len(result.FileStats))
+			}
+		})
+	}
+}
+
+func TestBuildTreeSameResultConcurrent(t *testing.T) {
+	tb := NewTreeBuilder(NewHeatCalculator())
+	result := testResult(7)
+	want := wantFiles(result)
+
+	var wg sync.WaitGroup
+	roots := make([]*models.FileNode, 16)
+	for i := range roots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			roots[i] = tb.BuildTree(result)
+		}()
+	}
+	wg.Wait()
+
+	for i, root := range roots {
+		if got := treeFiles(t, root); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("build %d: files = %v, want %v", i, got, want)
+		}
+		for j := 0; j < i; j++ {
+			if roots[j] == root || roots[j].Children[0] == root.Children[0] {
+				t.Errorf("builds %d and %d share nodes", j, i)
+			}
+		}
+	}
+}
@@ -4,10 +4,18 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/gorilla/mux"
+
 	"github.com/richd0tcom/fire-sight/internal/analyzer"
 	"github.com/richd0tcom/fire-sight/internal/models"
 )
@@ -18,6 +26,7 @@ type Handler struct {
 	gitAnalyzer    *analyzer.GitAnalyzer
 	treeBuilder    *analyzer.TreeBuilder
 	timeout        time.Duration
+	results        *resultStore
 }
 
 func NewHandler(gitAnalyzer *analyzer.GitAnalyzer, treeBuilder *analyzer.TreeBuilder) *Handler {
@@ -25,15 +34,23 @@ func NewHandler(gitAnalyzer *analyzer.GitAnalyzer, treeBuilder *analyzer.TreeBui
 		gitAnalyzer:    gitAnalyzer,
 		treeBuilder:    treeBuilder,
 		timeout:        5 * time.Minute,
+		results:        newResultStore(),
 	}
 }
 
+// Upload limit for multipart analyze requests (coverage reports can be big)
+const maxUploadSize = 64 << 20
+
 // AnalyzeRepo handles POST /api/analyze
+//
+// The body is either the JSON request, or multipart/form-data with the JSON
+// in a "request" field, report files in "coverage" parts and pprof files
+// in "profile" parts. ?series=week|month adds change time series to nodes.
 func (h *Handler) AnalyzeRepo(w http.ResponseWriter, r *http.Request) {
 	// Parse request
-	var req models.AnalyzeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+	req, err := h.decodeAnalyzeRequest(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -43,6 +60,40 @@ func (h *Handler) AnalyzeRepo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Rules != nil {
+		if _, err := analyzer.NewRuleEngine(req.Rules); err != nil {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid rules: %v", err))
+			return
+		}
+	}
+
+	heatModel, err := analyzer.NewHeatModel(req.HeatModel)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid heat model: %v", err))
+		return
+	}
+
+	series := r.URL.Query().Get("series")
+	if err := analyzer.ValidateSeriesBucket(series); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := analyzer.ValidateSortBy(req.SortBy); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := analyzer.ParseCoverageReports(req.Coverage); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid coverage report: %v", err))
+		return
+	}
+
+	if _, err := analyzer.ParseProfiles(req.Profiles); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid profile: %v", err))
+		return
+	}
+
 	// Set defaults
 	if req.Branch == "" {
 		req.Branch = "main"
@@ -62,6 +113,12 @@ func (h *Handler) AnalyzeRepo(w http.ResponseWriter, r *http.Request) {
 		Branch:        req.Branch,
 		TimeRangeDays: req.TimeRangeDays,
 		AuthToken:     req.AuthToken,
+		Rules:         req.Rules,
+		Coverage:      req.Coverage,
+		Profiles:      req.Profiles,
+		HeatModel:     req.HeatModel,
+		SortBy:        req.SortBy,
+		Series:        models.SeriesBucket(series),
 	}
 
 	result, err := h.gitAnalyzer.AnalyzeRepository(ctx, req.RepoURL, opts)
@@ -74,18 +131,175 @@ func (h *Handler) AnalyzeRepo(w http.ResponseWriter, r *http.Request) {
 
 
 	repoID := h.generateRepoID(req.RepoURL, req.Branch)
-
+	h.results.put(repoID, &storedResult{result: result, tree: fileTree})
 
 	response := models.AnalyzeResponse{
 		RepoID:    repoID,
 		Status:    "complete",
 		FileTree: fileTree,
+		HeatModel: heatModel.Config(),
 		Duration:  time.Since(startTime).String(),
 	}
 
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// decodeAnalyzeRequest reads a JSON body or a multipart upload
+func (h *Handler) decodeAnalyzeRequest(r *http.Request) (models.AnalyzeRequest, error) {
+	var req models.AnalyzeRequest
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, errors.New("Invalid request body")
+		}
+		return req, nil
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return req, fmt.Errorf("Invalid multipart body: %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(r.FormValue("request")), &req); err != nil {
+		return req, errors.New("Invalid request field")
+	}
+
+	for _, header := range r.MultipartForm.File["coverage"] {
+		content, err := readUpload(header)
+		if err != nil {
+			return req, fmt.Errorf("Invalid coverage upload %s: %v", header.Filename, err)
+		}
+
+		req.Coverage = append(req.Coverage, models.CoverageReport{
+			Format:  r.FormValue("coverageFormat"),
+			Name:    header.Filename,
+			Content: string(content),
+		})
+	}
+
+	for _, header := range r.MultipartForm.File["profile"] {
+		content, err := readUpload(header)
+		if err != nil {
+			return req, fmt.Errorf("Invalid profile upload %s: %v", header.Filename, err)
+		}
+
+		req.Profiles = append(req.Profiles, models.ProfileReport{
+			Name:    header.Filename,
+			Content: content,
+		})
+	}
+
+	return req, nil
+}
+
+func readUpload(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// ListOrphans handles GET /repos/{repoId}/orphans
+//
+// Lists the files of the last analysis of that repo that no entrypoint
+// file imports, coldest first
+func (h *Handler) ListOrphans(w http.ResponseWriter, r *http.Request) {
+	repoID := mux.Vars(r)["repoId"]
+
+	stored, ok := h.results.get(repoID)
+	if !ok {
+		h.respondError(w, http.StatusNotFound, fmt.Sprintf("Unknown repo %s, POST /analyze first", repoID))
+		return
+	}
+
+	orphans := []models.OrphanFile{}
+	var walk func(node *models.FileNode)
+	walk = func(node *models.FileNode) {
+		if node == nil {
+			return
+		}
+		if node.Type == models.FileNodeTypeFile && node.Orphan != nil && *node.Orphan {
+			orphan := models.OrphanFile{
+				Path:               node.Path,
+				Language:           node.Language,
+				LastModified:       node.LastModified,
+				IsDeadCode:         node.IsDeadCode,
+				DeadCodeConfidence: node.DeadCodeConfidence,
+			}
+			if node.HeatScore != nil {
+				orphan.HeatScore = node.HeatScore.Score
+				orphan.DaysSinceEdit = node.HeatScore.DaysSinceEdit
+			}
+			orphans = append(orphans, orphan)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(stored.tree)
+
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].HeatScore != orphans[j].HeatScore {
+			return orphans[i].HeatScore < orphans[j].HeatScore
+		}
+		return orphans[i].Path < orphans[j].Path
+	})
+
+	h.respondJSON(w, http.StatusOK, models.OrphansResponse{
+		RepoID:  repoID,
+		Total:   len(orphans),
+		Orphans: orphans,
+	})
+}
+
+// Default number of entries per list in coupling responses
+const defaultCouplingLimit = 20
+
+// ListCouplings handles GET /repos/{repoId}/coupling
+//
+// Returns the strongest couplings across directory boundaries of the last
+// analysis of that repo; ?file=path adds that file's strongest partners,
+// ?limit=n caps both lists (20 by default)
+func (h *Handler) ListCouplings(w http.ResponseWriter, r *http.Request) {
+	repoID := mux.Vars(r)["repoId"]
+
+	limit := defaultCouplingLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid limit %q", value))
+			return
+		}
+		limit = n
+	}
+
+	stored, ok := h.results.get(repoID)
+	if !ok {
+		h.respondError(w, http.StatusNotFound, fmt.Sprintf("Unknown repo %s, POST /analyze first", repoID))
+		return
+	}
+
+	response := models.CouplingResponse{
+		RepoID:            repoID,
+		TopCrossDirectory: analyzer.CrossDirectoryCouplings(stored.result.Couplings, limit),
+	}
+
+	if file := r.URL.Query().Get("file"); file != "" {
+		if _, exists := stored.result.FileStats[file]; !exists {
+			h.respondError(w, http.StatusNotFound, fmt.Sprintf("Unknown file %s", file))
+			return
+		}
+		response.File = file
+		response.Partners = analyzer.CoupledPartners(stored.result.Couplings, file, limit)
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
 // HealthCheck handles GET /health
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	response := map[string]string{
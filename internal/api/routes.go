@@ -13,6 +13,8 @@ func SetupRoutes(h *Handler) *mux.Router {
 	r.Use(corsMiddleware)
 
 	r.HandleFunc("/analyze", h.AnalyzeRepo).Methods("POST")
+	r.HandleFunc("/repos/{repoId}/orphans", h.ListOrphans).Methods("GET")
+	r.HandleFunc("/repos/{repoId}/coupling", h.ListCouplings).Methods("GET")
 	r.HandleFunc("/health", h.HealthCheck).Methods("GET")
 
 	r.Methods(http.MethodOptions).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
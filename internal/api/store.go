@@ -0,0 +1,59 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/richd0tcom/fire-sight/internal/models"
+)
+
+// How many analyses the server keeps for follow-up GET requests
+const maxStoredResults = 32
+
+// storedResult is what follow-up endpoints need from an analysis
+type storedResult struct {
+	result *models.AnalysisResult
+	tree   *models.FileNode
+}
+
+// resultStore keeps the latest analyses in memory, keyed by repo ID.
+// The oldest entry goes first once the store is full.
+type resultStore struct {
+	mu      sync.RWMutex
+	entries map[string]*storedResult
+	order   []string // insertion order, oldest first
+}
+
+func newResultStore() *resultStore {
+	return &resultStore{entries: make(map[string]*storedResult)}
+}
+
+func (s *resultStore) put(repoID string, entry *storedResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[repoID]; exists {
+		// Re-analysis: move to the back
+		for i, id := range s.order {
+			if id == repoID {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+	}
+
+	s.entries[repoID] = entry
+	s.order = append(s.order, repoID)
+
+	for len(s.order) > maxStoredResults {
+		delete(s.entries, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *resultStore) get(repoID string) (*storedResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[repoID]
+	return entry, ok
+}
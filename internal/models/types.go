@@ -14,6 +14,163 @@ type AnalyzeOptions struct {
 	TimeRangeDays int
 
 	AuthToken string
+
+	// Dead-code rules from the request, checked before the repo's .firesight.yaml
+	Rules *RulesConfig
+
+	// Test coverage reports to map onto files and functions
+	Coverage []CoverageReport
+
+	// pprof profiles; functions with samples are never dead
+	Profiles []ProfileReport
+
+	// How changes turn into heat, nil for the default exponential decay
+	HeatModel *HeatModelConfig
+
+	// File order in the tree, see SortByHeat
+	SortBy string
+
+	// Time series bucket size, empty for no series
+	Series SeriesBucket
+}
+
+// CoverageReport is one uploaded coverage file
+type CoverageReport struct {
+	Format  string `json:"format,omitempty"` // go | lcov | cobertura, detected from content when empty
+	Name    string `json:"name,omitempty"`   // for error messages
+	Content string `json:"content"`
+}
+
+// ProfileReport is one uploaded pprof profile (CPU, heap, ...)
+type ProfileReport struct {
+	Name    string `json:"name,omitempty"` // for error messages
+	Content []byte `json:"content"`        // raw or gzipped protobuf, base64 in JSON
+}
+
+// Heat model names
+const (
+	HeatModelExponential = "exponential" // weight halves every halfLifeDays
+	HeatModelLinear      = "linear"      // weight falls from 1 to 0 over windowDays
+	HeatModelStep        = "step"        // fixed weight per age bucket
+	HeatModelCount       = "count"       // every change counts 1, however old
+)
+
+// HeatModelConfig selects a heat model and its parameters. Unset
+// parameters get the model's defaults; responses echo the resolved values.
+type HeatModelConfig struct {
+	Model        string       `json:"model"`
+	HalfLifeDays float64      `json:"halfLifeDays,omitempty"` // exponential
+	WindowDays   int          `json:"windowDays,omitempty"`   // linear
+	Buckets      []HeatBucket `json:"buckets,omitempty"`      // step
+
+	// Cap on the file bonus of +0.1 per distinct author (0.5 by default, 0 turns it off)
+	AuthorBonusCap *float64 `json:"authorBonusCap,omitempty"`
+
+	// What a day of changes is worth before the age weight: commits (default) or churned lines
+	Weighting    string `json:"weighting,omitempty"`
+	LogDampening bool   `json:"logDampening,omitempty"` // churn only: log2(1 + lines) per day
+
+	// How raw scores map to 0-100: max (default), percentile, log, zscore or absolute (raw, unbounded)
+	Normalization string `json:"normalization,omitempty"`
+}
+
+// Heat score normalizations
+const (
+	NormalizationMax        = "max"
+	NormalizationPercentile = "percentile"
+	NormalizationLog        = "log"
+	NormalizationZScore     = "zscore"
+	NormalizationAbsolute   = "absolute"
+)
+
+// Heat weightings
+const (
+	HeatWeightingCommits = "commits"
+	HeatWeightingChurn   = "churn"
+)
+
+// HeatBucket weighs changes at most MaxDays old (checked in ascending order)
+type HeatBucket struct {
+	MaxDays int     `json:"maxDays"`
+	Weight  float64 `json:"weight"`
+}
+
+// RulesConfig is the content of a .firesight.yaml file (or the "rules"
+// field of an analyze request)
+type RulesConfig struct {
+	DeadCode DeadCodeRules `json:"deadCode" yaml:"deadCode"`
+	Commits  CommitRules   `json:"commits" yaml:"commits"`
+	Ownership OwnershipRules `json:"ownership" yaml:"ownership"`
+}
+
+// OwnershipRules tune the ownership metrics
+type OwnershipRules struct {
+	// Days without a commit anywhere in the repo after which a dominant author's knowledge is at risk (90)
+	KnowledgeAtRiskDays *int `json:"knowledgeAtRiskDays,omitempty" yaml:"knowledgeAtRiskDays"`
+}
+
+// CommitKind is what a commit was for, read from its message
+type CommitKind string
+
+const (
+	CommitFix      CommitKind = "fix"
+	CommitFeature  CommitKind = "feature"
+	CommitRefactor CommitKind = "refactor"
+	CommitChore    CommitKind = "chore"
+	CommitOther    CommitKind = "other" // nothing matched
+)
+
+// CommitKinds that patterns and issue keys can be configured for, in the order they are checked
+var CommitKinds = []CommitKind{CommitFix, CommitFeature, CommitRefactor, CommitChore}
+
+// CommitRules tune commit classification, e.g.
+//
+//	patterns:
+//	  fix: ["(?i)\\bwrong\\b"]
+//	issueKeys:
+//	  fix: ["SUP"]   # SUP-123 -> fix
+type CommitRules struct {
+	// Regexes on the whole message, checked before anything built in
+	Patterns map[CommitKind][]string `json:"patterns,omitempty" yaml:"patterns"`
+
+	// Issue key prefixes; BUG, DEFECT, HOTFIX and INCIDENT always mean fix
+	IssueKeys map[CommitKind][]string `json:"issueKeys,omitempty" yaml:"issueKeys"`
+}
+
+type DeadCodeRules struct {
+	Defaults DeadCodeSettings `json:"defaults" yaml:"defaults"`
+
+	// Checked top to bottom, first match decides
+	Rules []DeadCodeRule `json:"rules,omitempty" yaml:"rules"`
+}
+
+// DeadCodeSettings tune the signals. Unset fields fall back to the
+// defaults section, then to the built-in values.
+type DeadCodeSettings struct {
+	InactiveDays *int     `json:"inactiveDays,omitempty" yaml:"inactiveDays"` // "inactive" fires after this many days without changes
+	MinChanges   *int     `json:"minChanges,omitempty" yaml:"minChanges"`     // "few-changes" fires below this many changes
+	Threshold    *float64 `json:"threshold,omitempty" yaml:"threshold"`       // weighted signal sum needed for a dead verdict
+
+	Weights map[DeadCodeReason]float64 `json:"weights,omitempty" yaml:"weights"`
+
+	// Entrypoints and exported API are never dead unless opted in here
+	IncludeEntrypoints *bool `json:"includeEntrypoints,omitempty" yaml:"includeEntrypoints"`
+	IncludeExported    *bool `json:"includeExported,omitempty" yaml:"includeExported"`
+}
+
+// DeadCodeRule applies settings to paths and/or languages, e.g.
+//
+//	- paths: ["migrations/**"]
+//	  never: true
+//	- paths: ["internal/legacy/**"]
+//	  inactiveDays: 90
+type DeadCodeRule struct {
+	Name      string   `json:"name,omitempty" yaml:"name"`
+	Paths     []string `json:"paths,omitempty" yaml:"paths"`         // gitignore-style globs, any may match
+	Languages []string `json:"languages,omitempty" yaml:"languages"` // any may match
+	Never     bool     `json:"never,omitempty" yaml:"never"`         // never report matching nodes as dead
+
+	DeadCodeSettings `yaml:",inline"`
 }
 
 type FileChangeStats struct {
@@ -22,23 +179,105 @@ type FileChangeStats struct {
 	LastModified time.Time
 	ChangesByDay map[int]int
 
+	// Commits by kind, and fix commits per day bucket (days ago -> fixes)
+	CommitKinds  map[CommitKind]int
+	FixCommits   int
+	FixesByDay   map[int]int
+
+	// Line churn from commit stats, in total and per day bucket (days ago -> lines)
+	LinesAdded   int
+	LinesRemoved int
+	AddedByDay   map[int]int
+	RemovedByDay map[int]int
+
 	//map of authors and commit count
 	UniqueAuthors map[string]int
 	FirstSeen     time.Time
+
+	// In the tree but unchanged in the window; LastModified comes from the
+	// full history (zero when no commit on the branch has it)
+	NoChangesInWindow bool
 }
 
 type FunctionStats struct {
 	Name         string
+	Type         string // function, method, closure, struct, interface, cell
+	Cell         int    // notebook cell index (1-based), 0 outside notebooks
 	LineStart    int
 	LineEnd      int
 	TotalChanges int
 	LastModified time.Time
 	ChangesByDay map[int]int // days ago -> change count
+
+	// Fix commits that touched it, in total and per day
+	FixCommits int
+	FixesByDay map[int]int
+
+	// Static call-graph verdict (Go only), nil when not analyzed
+	Reachable *bool
+
+	// Textual references from outside the definition (JS/TS, Python), nil when not indexed
+	References *int
+
+	// Percent of instrumented lines covered by tests, nil without a coverage report
+	Coverage *float64
+
+	// Percent of profile samples with it on the stack, nil without a profile
+	RuntimeShare *float64
+
+	// Cyclomatic complexity, nesting and length; nil for type declarations
+	Complexity *Complexity
+
+	// entrypoint | exported | test | internal
+	Role FunctionRole
+
+	// firesight:ignore / firesight:keep annotation, nil when none
+	Suppression *Suppression
+}
+
+type FunctionRole string
+
+const (
+	RoleEntrypoint FunctionRole = "entrypoint" // main, init, route handlers, __main__ calls
+	RoleExported   FunctionRole = "exported"   // public API other code may import
+	RoleTest       FunctionRole = "test"       // lives in a test file
+	RoleInternal   FunctionRole = "internal"
+)
+
+type SuppressionKind string
+
+const (
+	SuppressionIgnore SuppressionKind = "ignore" // listed, but left out of scoring and verdicts
+	SuppressionKeep   SuppressionKind = "keep"   // scored, but never reported dead
+)
+
+const (
+	SuppressionSourceAnnotation = "annotation"
+	SuppressionSourceIgnoreFile = ".firesightignore"
+)
+
+// Suppression records why a node is exempt from (parts of) the analysis
+type Suppression struct {
+	Kind   SuppressionKind `json:"kind"`
+	Source string          `json:"source"`           // annotation | .firesightignore
+	Reason string          `json:"reason,omitempty"` // annotation note or matching pattern
+}
+
+// Ignored reports whether s leaves the node out of scoring
+func (s *Suppression) Ignored() bool {
+	return s != nil && s.Kind == SuppressionIgnore
 }
 
 type FileAnalysis struct {
 	Path      string
 	Language  string
+	Vendored  bool
+	Generated bool
+	Coverage  *float64 // percent, nil without a coverage report
+	RuntimeShare *float64 // percent of profile samples, nil without a profile
+	Suppression *Suppression // matched by .firesightignore
+	Orphan    *bool // unreachable in the import graph, nil for languages without one
+	Complexity *Complexity // roll-up of the units, nil when nothing was parsed
 	Functions []*FunctionStats
 }
 
@@ -56,16 +295,82 @@ type AnalysisResult struct {
 
 	Status        string `json:"status"`
 	TimeRangeDays int    `json:"timeRangeDays"`
+
+	// Effective dead-code rules (request rules + repo .firesight.yaml)
+	Rules *RulesConfig `json:"rules,omitempty"`
+
+	// Heat model requested for this analysis, nil for the default
+	HeatModel *HeatModelConfig `json:"heatModel,omitempty"`
+
+	// How files are ordered in the tree: heat (default) or hotspot
+	SortBy string `json:"sortBy,omitempty"`
+
+	// Bucket size of node time series, empty for none
+	Series SeriesBucket `json:"series,omitempty"`
+
+	// Each author's latest commit in the window, across all files
+	AuthorLastCommit map[string]time.Time `json:"authorLastCommit,omitempty"`
+
+	// File pairs that changed together, strongest first
+	Couplings []FileCoupling `json:"couplings,omitempty"`
+}
+
+// FileCoupling is a pair of files that keep changing in the same commits
+type FileCoupling struct {
+	FileA   string  `json:"fileA"`
+	FileB   string  `json:"fileB"`
+	Support int     `json:"support"` // commits that changed both
+	Degree  float64 `json:"degree"`  // percent: support / average revisions of the two
 }
 
 type HeatScore struct {
 	Path          string        `json:"path"`
 	Score         float64       `json:"score"` // 0-100
+	RawScore      float64       `json:"rawScore"` // before normalization
 	ChangeFreq    float64       `json:"changeFreq"` //TODO: compare with int
 	DaysSinceEdit int           `json:"daysSinceEdit"`
 	TotalFileChanges int `json:"totalFileChanges"`
+	LinesAdded    int           `json:"linesAdded,omitempty"`
+	LinesRemoved  int           `json:"linesRemoved,omitempty"`
+
+	// Fix commits, and heat computed from them alone
+	FixCommits    int           `json:"fixCommits"`
+	DefectHeat    float64       `json:"defectHeat"`
+
+	// Changes in the recent and the older half of the window, and what they say
+	Trend         Trend         `json:"trend,omitempty"`
+	RecentChanges int           `json:"recentChanges"`
+	OlderChanges  int           `json:"olderChanges"`
+
+	// Nothing changed the file in the window: score 0, off the heat scale
+	NoChangesInWindow bool      `json:"noChangesInWindow,omitempty"`
+}
+
+// SeriesBucket is the bucket size of heat time series
+type SeriesBucket string
+
+const (
+	SeriesWeekly  SeriesBucket = "week"
+	SeriesMonthly SeriesBucket = "month"
+)
+
+// SeriesPoint is one bucket of a node's change history
+type SeriesPoint struct {
+	Start        time.Time `json:"start"`
+	Changes      int       `json:"changes"`
+	LinesAdded   int       `json:"linesAdded,omitempty"`
+	LinesRemoved int       `json:"linesRemoved,omitempty"`
 }
 
+// Trend compares the recent half of the analysis window with the older half
+type Trend string
+
+const (
+	TrendRising  Trend = "rising"
+	TrendSteady  Trend = "steady"
+	TrendCooling Trend = "cooling"
+)
+
 
 // FileNode represents a file or folder in the tree structure
 type FileNode struct {
@@ -74,6 +379,20 @@ type FileNode struct {
 	Path            string          `json:"path"`
 	Type            FileNodeType    `json:"type"` // "file" | "folder"
 	Extension       string          `json:"extension,omitempty"`
+	Language        string          `json:"language,omitempty"`
+	Vendored        bool            `json:"vendored,omitempty"`
+	Generated       bool            `json:"generated,omitempty"`
+	Coverage        *float64        `json:"coverage,omitempty"` // percent of instrumented lines covered
+	RuntimeShare    *float64        `json:"runtimeShare,omitempty"` // percent of profile samples with this file on the stack
+	Suppression     *Suppression    `json:"suppression,omitempty"`  // .firesightignore match
+	Orphan          *bool           `json:"orphan,omitempty"`       // no entrypoint file imports it, even indirectly
+	Complexity      *Complexity     `json:"complexity,omitempty"`   // rolled up from the file's units
+	HotspotScore    float64         `json:"hotspotScore"`           // heat x complexity, normalized like heat
+	HotspotRank     int             `json:"hotspotRank,omitempty"`  // 1 = riskiest file in the repo, 0 when not ranked
+	Series          []SeriesPoint   `json:"series,omitempty"`       // changes and churn per bucket, oldest first (?series=week|month)
+	CommitKinds     map[CommitKind]int `json:"commitKinds,omitempty"` // commits by kind (folders: summed)
+	Authors         map[string]int  `json:"authors,omitempty"`      // commits by author (folders: summed)
+	Ownership       *Ownership      `json:"ownership,omitempty"`    // knowledge concentration, nil without changes
 	Size            int64           `json:"size"`
 	LinesOfCode     int             `json:"linesOfCode"`
 	LastModified    time.Time       `json:"lastModified"`
@@ -81,18 +400,115 @@ type FileNode struct {
 	Functions       []*FunctionNode `json:"functions,omitempty"`
 	Children        []*FileNode     `json:"children,omitempty"`
 	FileCount       int             `json:"fileCount,omitempty"` // For folders: total files inside
+
+	// Files: dead-code verdict and the signals that fired
+	IsDeadCode      bool             `json:"isDeadCode"`
+	DeadCodeReasons []DeadCodeReason `json:"deadCodeReasons,omitempty"`
+	DeadCodeRule    string           `json:"deadCodeRule,omitempty"` // rule that decided the verdict
+
+	// 0-1, for ranking cleanup candidates; signals explain the number
+	DeadCodeConfidence float64          `json:"deadCodeConfidence"`
+	DeadCodeSignals    []DeadCodeSignal `json:"deadCodeSignals,omitempty"`
+
+	// Folders: cleanup candidates inside (recursive)
+	DeadFileCount     int `json:"deadFileCount,omitempty"`
+	DeadFunctionCount int `json:"deadFunctionCount,omitempty"`
+}
+
+// DeadCodeReason names a signal that contributed to a dead-code verdict
+type DeadCodeReason string
+
+const (
+	DeadCodeReasonInactive     DeadCodeReason = "inactive"      // no changes in 6+ months (configurable)
+	DeadCodeReasonFewChanges   DeadCodeReason = "few-changes"   // fewer than 3 changes (configurable)
+	DeadCodeReasonSingleAuthor DeadCodeReason = "single-author" // one author, maybe an abandoned experiment
+	DeadCodeReasonUncovered    DeadCodeReason = "uncovered"     // no test runs it (needs a coverage report)
+	DeadCodeReasonOrphan       DeadCodeReason = "orphan"        // unreachable from entrypoint files in the import graph
+	DeadCodeReasonUnreachable  DeadCodeReason = "unreachable"   // Go call graph can't reach it (functions, only when inactive)
+	DeadCodeReasonUnreferenced DeadCodeReason = "unreferenced"  // no JS/TS/Python file mentions it (functions, only when inactive)
+)
+
+// DeadCodeReasons that rules can weigh
+var DeadCodeReasons = []DeadCodeReason{
+	DeadCodeReasonInactive, DeadCodeReasonFewChanges, DeadCodeReasonSingleAuthor, DeadCodeReasonUncovered,
+	DeadCodeReasonOrphan, DeadCodeReasonUnreachable, DeadCodeReasonUnreferenced,
+}
+
+type DeadCodeSignalName string
+
+const (
+	SignalAge        DeadCodeSignalName = "age"        // days since last edit
+	SignalChanges    DeadCodeSignalName = "changes"    // changes in the analysis window
+	SignalAuthors    DeadCodeSignalName = "authors"    // distinct authors (files only)
+	SignalReferences DeadCodeSignalName = "references" // call-graph / symbol-index references
+	SignalCoverage   DeadCodeSignalName = "coverage"   // percent covered by tests
+	SignalRuntime    DeadCodeSignalName = "runtime"    // percent of profile samples
+	SignalEntrypoint DeadCodeSignalName = "entrypoint" // called from outside (entrypoint or exported API)
+	SignalImports    DeadCodeSignalName = "imports"    // reachable from entrypoint files (files only)
+)
+
+// DeadCodeSignal is one input of the dead-code confidence
+type DeadCodeSignal struct {
+	Signal       DeadCodeSignalName `json:"signal"`
+	Value        float64            `json:"value"`        // raw input: days, count, ...
+	Score        float64            `json:"score"`        // 0-1, how dead this signal alone says the node is
+	Weight       float64            `json:"weight"`
+	Contribution float64            `json:"contribution"` // share of the confidence (contributions sum to it)
 }
 
 // FunctionNode represents a function/method within a file (Milestone 2)
 type FunctionNode struct {
 	Name            string    `json:"name"`
+	Type            string    `json:"type,omitempty"` // function | method | closure | struct | interface | cell
+	Role            FunctionRole `json:"role,omitempty"` // entrypoint | exported | test | internal
+	Cell            int       `json:"cell,omitempty"` // notebooks: lines are relative to this cell
 	LineStart       int       `json:"lineStart"`
 	LineEnd         int       `json:"lineEnd"`
 	LastModified    time.Time `json:"lastModified"`
 	HeatScore       *HeatScore   `json:"heatScore"`
 	IsDeadCode      bool      `json:"isDeadCode"`
+	DeadCodeRule    string    `json:"deadCodeRule,omitempty"` // rule that decided the verdict
+	DeadCodeConfidence float64          `json:"deadCodeConfidence"` // 0-1
+	DeadCodeSignals    []DeadCodeSignal `json:"deadCodeSignals,omitempty"`
+	Reachable       *bool     `json:"reachable,omitempty"` // Go call-graph verdict
+	References      *int      `json:"references,omitempty"` // JS/TS, Python symbol index
+	Coverage        *float64  `json:"coverage,omitempty"` // percent of instrumented lines covered
+	RuntimeShare    *float64  `json:"runtimeShare,omitempty"` // percent of profile samples with it on the stack
+	Suppression     *Suppression `json:"suppression,omitempty"` // firesight:ignore / firesight:keep
+	Complexity      *Complexity  `json:"complexity,omitempty"`
+	HotspotScore    float64      `json:"hotspotScore"` // heat x complexity, within the file
+	Series          []SeriesPoint `json:"series,omitempty"` // changed lines per bucket, oldest first
+}
+
+// Ownership measures how concentrated the knowledge of a file or folder is
+type Ownership struct {
+	Authors             int       `json:"authors"`
+	TopAuthor           string    `json:"topAuthor"`
+	TopAuthorShare      float64   `json:"topAuthorShare"`      // percent of changes
+	TopAuthorLastCommit time.Time `json:"topAuthorLastCommit"` // on the analyzed branch, within the window
+	AuthorsFor80        int       `json:"authorsFor80"`        // fewest authors covering 80% of changes
+	BusFactor           int       `json:"busFactor"`           // fewest authors covering more than half of the changes
+
+	// The top author has made no commit on the branch for knowledgeAtRiskDays
+// (never set when the window is shorter than that)
+	KnowledgeAtRisk bool `json:"knowledgeAtRisk"`
+	AtRiskFiles     int  `json:"atRiskFiles,omitempty"` // folders: files at risk inside (recursive)
+}
+
+// Complexity of a unit, or the roll-up of a file's units
+type Complexity struct {
+	Cyclomatic    int `json:"cyclomatic"`              // 1 + decision points; files: sum over units
+	MaxNesting    int `json:"maxNesting"`              // deepest block nesting
+	Lines         int `json:"lines"`                   // lines spanned; files: sum over units
+	MaxCyclomatic int `json:"maxCyclomatic,omitempty"` // files: most complex unit
 }
 
+// Tree orderings for files within a folder
+const (
+	SortByHeat    = "heat"    // hottest first (default)
+	SortByHotspot = "hotspot" // best hotspot rank first
+)
+
 type Changes struct {
 	FilePath string
 }
@@ -103,6 +519,21 @@ type AnalyzeRequest struct {
 	Branch        string `json:"branch"`               // default: "main"
 	TimeRangeDays int    `json:"timeRangeDays"`      // default: 180
 	AuthToken     string `json:"authToken,omitempty"` // for private repos
+
+	// Same shape as .firesight.yaml; these rules win over the repo's
+	Rules *RulesConfig `json:"rules,omitempty"`
+
+	// Coverage reports inline; multipart uploads add theirs here too
+	Coverage []CoverageReport `json:"coverage,omitempty"`
+
+	// pprof profiles inline (base64); multipart uploads add theirs here too
+	Profiles []ProfileReport `json:"profiles,omitempty"`
+
+	// Heat model, exponential decay with a ~69 day half-life by default
+	HeatModel *HeatModelConfig `json:"heatModel,omitempty"`
+
+	// File order in the tree: heat (default) or hotspot
+	SortBy string `json:"sortBy,omitempty"`
 }
 
 type AnalyzeResponse struct {
@@ -110,7 +541,39 @@ type AnalyzeResponse struct {
 	Status    string      `json:"status"` // "complete" | "error"
 	FileStats []HeatScore `json:"fileStats"`
 	FileTree  *FileNode   `json:"fileTree,omitempty"` // NEW: Hierarchical tree
+	HeatModel *HeatModelConfig `json:"heatModel,omitempty"` // model and parameters the scores used
 	Analyzed  int         `json:"analyzedFiles"`
 	Error     string      `json:"error,omitempty"`
 	Duration  string      `json:"duration"`
 }
+
+// OrphanFile is one entry of GET /repos/{repoId}/orphans
+type OrphanFile struct {
+	Path               string    `json:"path"`
+	Language           string    `json:"language,omitempty"`
+	HeatScore          float64   `json:"heatScore"`
+	DaysSinceEdit      int       `json:"daysSinceEdit"`
+	LastModified       time.Time `json:"lastModified"`
+	IsDeadCode         bool      `json:"isDeadCode"`
+	DeadCodeConfidence float64   `json:"deadCodeConfidence"`
+}
+
+type OrphansResponse struct {
+	RepoID  string       `json:"repoId"`
+	Total   int          `json:"total"`
+	Orphans []OrphanFile `json:"orphans"` // coldest first
+}
+
+// CoupledFile is a partner of the file asked for in GET /repos/{repoId}/coupling
+type CoupledFile struct {
+	Path    string  `json:"path"`
+	Support int     `json:"support"`
+	Degree  float64 `json:"degree"`
+}
+
+type CouplingResponse struct {
+	RepoID            string         `json:"repoId"`
+	File              string         `json:"file,omitempty"`
+	Partners          []CoupledFile  `json:"partners,omitempty"` // strongest first, with ?file=
+	TopCrossDirectory []FileCoupling `json:"topCrossDirectory"`  // strongest pairs across directories
+}
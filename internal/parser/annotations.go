@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Annotation is an in-source instruction for the analyzer
+//
+//	// firesight:keep constant-time compare, rarely touched on purpose
+//	func compare(a, b []byte) bool {
+//
+//	def legacy_stub():  # firesight:ignore generated by protoc
+type Annotation string
+
+const (
+	AnnotationIgnore Annotation = "ignore" // leave out of scoring entirely
+	AnnotationKeep   Annotation = "keep"   // score it, but never report it dead
+)
+
+// Any single-line comment style: //, #, /*, *, --, ;, <!--
+var annotationPattern = regexp.MustCompile(`(?://|#|/\*|\*|--|;|<!--)\s*firesight:(ignore|keep)\b[:\s]*(.*?)\s*(?:\*/|-->)?\s*$`)
+
+// Annotate looks for annotations on a unit's first line, then in the
+// comment/decorator block right above it (the first one found wins)
+func Annotate(functions []*Function, content []byte) {
+	lines := strings.Split(string(content), "\n")
+
+	for _, fn := range functions {
+		if fn.LineStart < 1 || fn.LineStart > len(lines) {
+			continue
+		}
+
+		for i := fn.LineStart - 1; i >= 0; i-- {
+			if i < fn.LineStart-1 && !isPreambleLine(lines[i]) {
+				break
+			}
+
+			if matches := annotationPattern.FindStringSubmatch(lines[i]); matches != nil {
+				fn.Annotation = Annotation(matches[1])
+				fn.AnnotationNote = trimJSONLine(matches[2])
+				break
+			}
+		}
+	}
+}
+
+// trimJSONLine drops the quoting notebook JSON adds: `note\n",` -> `note`
+func trimJSONLine(note string) string {
+	note = strings.TrimSuffix(note, ",")
+	note = strings.TrimSuffix(note, `"`)
+	note = strings.TrimSuffix(note, `\n`)
+	return strings.TrimSpace(note)
+}
+
+// isPreambleLine: comments, doc comments and decorators may sit between an
+// annotation and the unit; blank lines and code may not
+func isPreambleLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	// Notebook JSON keeps source lines in strings: "    # comment\n",
+	trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, `"`))
+
+	for _, prefix := range []string{"//", "#", "/*", "*", "--", ";", "<!--", "@"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
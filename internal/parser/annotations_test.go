@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		lineStart  int
+		annotation Annotation
+		note       string
+	}{
+		{
+			name: "go comment above",
+			src: `// firesight:keep constant-time compare
+func compare(a, b []byte) bool {`,
+			lineStart: 2, annotation: AnnotationKeep, note: "constant-time compare",
+		},
+		{
+			name: "above the doc comment",
+			src: `// firesight:ignore: generated
+// Marshal encodes the message.
+//
+// It is generated.
+func Marshal() {}`,
+			lineStart: 5, annotation: AnnotationIgnore, note: "generated",
+		},
+		{
+			name:      "python trailing comment",
+			src:       `def legacy_stub():  # firesight:ignore generated by protoc`,
+			lineStart: 1, annotation: AnnotationIgnore, note: "generated by protoc",
+		},
+		{
+			name: "through decorators",
+			src: `# firesight:keep called by the scheduler
+@app.task
+@retry(3)
+def nightly():`,
+			lineStart: 4, annotation: AnnotationKeep, note: "called by the scheduler",
+		},
+		{
+			name: "block comment styles",
+			src: `/* firesight:keep */
+function a() {}`,
+			lineStart: 2, annotation: AnnotationKeep, note: "",
+		},
+		{
+			name: "html comment",
+			src: `<!-- firesight:ignore demo only -->
+<script>`,
+			lineStart: 2, annotation: AnnotationIgnore, note: "demo only",
+		},
+		{
+			name: "the unit's own line wins",
+			src: `// firesight:ignore
+func f() { // firesight:keep hot path`,
+			lineStart: 2, annotation: AnnotationKeep, note: "hot path",
+		},
+		{
+			name: "blank line stops the walk",
+			src: `// firesight:keep
+
+func f() {}`,
+			lineStart: 3,
+		},
+		{
+			name: "code stops the walk",
+			src: `// firesight:keep
+var x = 1
+func f() {}`,
+			lineStart: 3,
+		},
+		{
+			name:      "not a comment",
+			src:       `log("firesight:keep")`,
+			lineStart: 1,
+		},
+		{
+			name:      "needs the whole word",
+			src:       `// firesight:keeper of things`,
+			lineStart: 1,
+		},
+		{
+			name: "notebook json",
+			src: `   "source": [
+    "# firesight:keep used by the dashboard\n",
+    "def plot(df):\n",`,
+			lineStart: 3, annotation: AnnotationKeep, note: "used by the dashboard",
+		},
+		{
+			name: "notebook json, last line",
+			src: `   "source": [
+    "def plot(df):  # firesight:ignore scratch"
+   ]`,
+			lineStart: 2, annotation: AnnotationIgnore, note: "scratch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := &Function{Name: "f", LineStart: tt.lineStart, LineEnd: tt.lineStart}
+			Annotate([]*Function{fn}, []byte(tt.src))
+
+			if fn.Annotation != tt.annotation || fn.AnnotationNote != tt.note {
+				t.Errorf("annotation = %q %q, want %q %q", fn.Annotation, fn.AnnotationNote, tt.annotation, tt.note)
+			}
+		})
+	}
+}
+
+func TestAnnotateOutOfRange(t *testing.T) {
+	fn := &Function{Name: "f", LineStart: 10, LineEnd: 12}
+	Annotate([]*Function{fn}, []byte(strings.Repeat("// firesight:keep\n", 3)))
+	if fn.Annotation != "" {
+		t.Errorf("annotation = %q for a unit past the end of the file", fn.Annotation)
+	}
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richd0tcom/fire-sight/pkg"
+)
+
+// Detection is the outcome of language detection for a single file
+type Detection struct {
+	Language  Language
+	Vendored  bool // linguist-vendored: third-party code, not ours to clean up
+	Generated bool // linguist-generated: produced by a tool, edits happen upstream
+}
+
+// attributeRule is one line of .gitattributes that touches linguist attributes
+type attributeRule struct {
+	glob      *pkg.Glob
+	language  Language
+	vendored  *bool
+	generated *bool
+}
+
+// LanguageDetector combines content-based detection with the repository's
+// .gitattributes overrides (linguist-language / -vendored / -generated)
+type LanguageDetector struct {
+	rules []attributeRule
+}
+
+// NewLanguageDetector loads <repoPath>/.gitattributes if it exists.
+// A missing or unreadable file just means "no overrides".
+func NewLanguageDetector(repoPath string) *LanguageDetector {
+	ld := &LanguageDetector{}
+
+	file, err := os.Open(filepath.Join(repoPath, ".gitattributes"))
+	if err != nil {
+		return ld
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseAttributeLine(scanner.Text()); ok {
+			ld.rules = append(ld.rules, rule)
+		}
+	}
+
+	return ld
+}
+
+// Detect resolves language and linguist flags for a file.
+// Later .gitattributes lines win over earlier ones, like in git.
+func (ld *LanguageDetector) Detect(filePath string, content []byte) Detection {
+	detection := Detection{Language: DetectLanguageFromContent(filePath, content)}
+
+	for _, rule := range ld.rules {
+		if !rule.glob.Match(filePath) {
+			continue
+		}
+
+		if rule.language != "" {
+			detection.Language = rule.language
+		}
+		if rule.vendored != nil {
+			detection.Vendored = *rule.vendored
+		}
+		if rule.generated != nil {
+			detection.Generated = *rule.generated
+		}
+	}
+
+	return detection
+}
+
+// parseAttributeLine parses e.g.
+//
+//	*.inc            linguist-language=C++
+//	third_party/**   linguist-vendored
+//	*.pb.go          linguist-generated=true
+//	docs/**          -linguist-vendored
+func parseAttributeLine(line string) (attributeRule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return attributeRule{}, false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return attributeRule{}, false
+	}
+
+	glob, err := pkg.CompileGlob(fields[0])
+	if err != nil {
+		return attributeRule{}, false
+	}
+
+	rule := attributeRule{glob: glob}
+	relevant := false
+
+	for _, attr := range fields[1:] {
+		name, value, hasValue := strings.Cut(attr, "=")
+
+		enabled := true
+		switch {
+		case strings.HasPrefix(name, "-"), strings.HasPrefix(name, "!"):
+			name = name[1:]
+			enabled = false
+		case hasValue:
+			enabled = value != "false"
+		}
+
+		switch name {
+		case "linguist-language":
+			if lang := LanguageFromName(value); hasValue && lang != LangUnknown {
+				rule.language = lang
+				relevant = true
+			}
+		case "linguist-vendored":
+			rule.vendored = &enabled
+			relevant = true
+		case "linguist-generated":
+			rule.generated = &enabled
+			relevant = true
+		}
+	}
+
+	return rule, relevant
+}
@@ -0,0 +1,260 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// LEARNING MOMENT: Counting Paths Without a Compiler
+//
+// Cyclomatic complexity (McCabe) is the number of independent paths through
+// a function: 1 + one per decision point (if, loop, case, catch, && / ||).
+//
+//   func grade(score int) string {     // 1
+//       if score > 90 && curved {      // +1 if, +1 &&
+//           return "A"
+//       }
+//       for _, b := range bonuses {    // +1 range
+//       ...                            // = 4
+//
+// Go units are measured on the AST. Everything else gets a token count on
+// the unit's lines, with strings and comments stripped first: keywords
+// like "if" or "except" and the operators && || and, or. Nesting depth
+// comes from braces, or for Python from lines opening a block (ending in ':').
+// Close enough to rank functions, which is all a hotspot needs.
+
+// Complexity of one unit
+type Complexity struct {
+	Cyclomatic int // 1 + decision points
+	MaxNesting int // deepest block nesting inside the body
+	Lines      int // lines the unit spans
+}
+
+var (
+	// Decision keywords of C-family languages, plus Ruby/Perl/shell spellings
+	cFamilyDecisions = regexp.MustCompile(`\b(?:if|elif|elsif|unless|for|foreach|while|until|case|when|catch|rescue)\b|&&|\|\||\s\?\s`)
+	pythonDecisions  = regexp.MustCompile(`\b(?:if|elif|for|while|except|case|and|or)\b`)
+
+	doubleQuoted = regexp.MustCompile(`"(?:\\.|[^"\\])*"`)
+	singleQuoted = regexp.MustCompile(`'(?:\\.|[^'\\])*'`)
+	backQuoted   = regexp.MustCompile("`[^`]*`")
+)
+
+// MeasureComplexity fills in Complexity for the units a parser didn't
+// measure itself. Type declarations have no control flow and stay nil.
+func MeasureComplexity(functions []*Function, content []byte, lang Language) {
+	lines := strings.Split(string(content), "\n")
+	python := lang == LangPython
+
+	for _, fn := range functions {
+		if fn.Complexity != nil || fn.Type == TypeStruct || fn.Type == TypeInterface {
+			continue
+		}
+		if fn.LineStart < 1 || fn.LineEnd < fn.LineStart || fn.LineEnd > len(lines) {
+			continue
+		}
+
+		fn.Complexity = measureLines(lines[fn.LineStart-1:fn.LineEnd], python)
+	}
+}
+
+// measureLines counts decision points and nesting in source lines
+func measureLines(lines []string, python bool) *Complexity {
+	c := &Complexity{Cyclomatic: 1, Lines: len(lines)}
+
+	decisions := cFamilyDecisions
+	if python {
+		decisions = pythonDecisions
+	}
+
+	depth := 0
+	bodyIndent, step := -1, 0
+	inBlockComment := false
+
+	for i, raw := range lines {
+		code := stripStrings(raw)
+
+		if python {
+			code = stripPythonComment(code)
+		} else {
+			code, inBlockComment = stripCComments(code, inBlockComment)
+		}
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+
+		c.Cyclomatic += len(decisions.FindAllString(code, -1))
+
+		if !python {
+			// The unit's own braces are depth 1
+			for _, r := range code {
+				switch r {
+				case '{':
+					depth++
+					if depth-1 > c.MaxNesting {
+						c.MaxNesting = depth - 1
+					}
+				case '}':
+					depth--
+				}
+			}
+			continue
+		}
+
+		// Python: a line ending in ':' opens a block one level below its own
+		if i == 0 {
+			continue // the def line
+		}
+		indent := len(code) - len(strings.TrimLeft(code, " \t"))
+		if bodyIndent < 0 {
+			bodyIndent = indent
+		}
+		if step == 0 && indent > bodyIndent {
+			step = indent - bodyIndent
+		}
+		if strings.HasSuffix(strings.TrimSpace(code), ":") {
+			level := 1
+			if step > 0 && indent > bodyIndent {
+				level += (indent - bodyIndent) / step
+			}
+			if level > c.MaxNesting {
+				c.MaxNesting = level
+			}
+		}
+	}
+
+	return c
+}
+
+func stripStrings(line string) string {
+	line = doubleQuoted.ReplaceAllString(line, `""`)
+	line = singleQuoted.ReplaceAllString(line, `''`)
+	return backQuoted.ReplaceAllString(line, "``")
+}
+
+func stripPythonComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// stripCComments drops // and /* */ comments, carrying block state across lines
+func stripCComments(line string, inBlock bool) (string, bool) {
+	var b strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		if inBlock {
+			if strings.HasPrefix(line[i:], "*/") {
+				inBlock = false
+				i++
+			}
+			continue
+		}
+		if strings.HasPrefix(line[i:], "//") {
+			break
+		}
+		if strings.HasPrefix(line[i:], "/*") {
+			inBlock = true
+			i++
+			continue
+		}
+		b.WriteByte(line[i])
+	}
+
+	return b.String(), inBlock
+}
+
+// goComplexity measures a function body on the AST. Function literals are
+// skipped: closures are units of their own.
+func goComplexity(fset *token.FileSet, node ast.Node, body *ast.BlockStmt) *Complexity {
+	c := &Complexity{
+		Cyclomatic: 1,
+		Lines:      fset.Position(node.End()).Line - fset.Position(node.Pos()).Line + 1,
+	}
+	if body == nil {
+		return c
+	}
+
+	var visit func(n ast.Node, depth int)
+	nest := func(depth int) {
+		if depth > c.MaxNesting {
+			c.MaxNesting = depth
+		}
+	}
+	visitAll := func(depth int, nodes ...ast.Node) {
+		for _, n := range nodes {
+			if n != nil {
+				visit(n, depth)
+			}
+		}
+	}
+
+	visit = func(root ast.Node, depth int) {
+		ast.Inspect(root, func(n ast.Node) bool {
+			switch s := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.BinaryExpr:
+				if s.Op == token.LAND || s.Op == token.LOR {
+					c.Cyclomatic++
+				}
+			case *ast.CaseClause:
+				if s.List != nil {
+					c.Cyclomatic++ // default adds no path
+				}
+			case *ast.CommClause:
+				if s.Comm != nil {
+					c.Cyclomatic++
+				}
+			case *ast.IfStmt:
+				// else-if chains stay at the same depth
+				for s != nil {
+					c.Cyclomatic++
+					nest(depth + 1)
+					visitAll(depth, s.Init, s.Cond)
+					visit(s.Body, depth+1)
+
+					next, chained := s.Else.(*ast.IfStmt)
+					if !chained && s.Else != nil {
+						visit(s.Else, depth+1)
+					}
+					s = next
+				}
+				return false
+			case *ast.ForStmt:
+				c.Cyclomatic++
+				nest(depth + 1)
+				visitAll(depth, s.Init, s.Cond, s.Post)
+				visit(s.Body, depth+1)
+				return false
+			case *ast.RangeStmt:
+				c.Cyclomatic++
+				nest(depth + 1)
+				visit(s.X, depth)
+				visit(s.Body, depth+1)
+				return false
+			case *ast.SwitchStmt:
+				nest(depth + 1)
+				visitAll(depth, s.Init, s.Tag)
+				visit(s.Body, depth+1)
+				return false
+			case *ast.TypeSwitchStmt:
+				nest(depth + 1)
+				visitAll(depth, s.Init, s.Assign)
+				visit(s.Body, depth+1)
+				return false
+			case *ast.SelectStmt:
+				nest(depth + 1)
+				visit(s.Body, depth+1)
+				return false
+			}
+			return true
+		})
+	}
+	visit(body, 0)
+
+	return c
+}
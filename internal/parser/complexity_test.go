@@ -0,0 +1,264 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoComplexity(t *testing.T) {
+	src := `package grades
+
+func grade(score int, curved bool, bonuses []int) string {
+	if score > 90 && curved {
+		return "A"
+	} else if score > 80 {
+		for _, b := range bonuses {
+			if b > 0 {
+				score += b
+			}
+		}
+	}
+
+	switch score {
+	case 1:
+	case 2, 3:
+	default:
+	}
+
+	f := func() {
+		if curved || score > 0 {
+		}
+	}
+	f()
+	return ""
+}
+
+func wait(a, b chan int) {
+	select {
+	case <-a:
+	case <-b:
+	default:
+	}
+}
+
+type Grade struct {
+	Score int
+}
+`
+
+	functions, err := NewGoParser().Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		cyclomatic int
+		nesting    int
+		lines      int
+	}{
+		// 1 + if, &&, else if, range, if, two cases; the closure is not counted
+		{"grade", 8, 3, 24},
+		{"grade.f", 3, 1, 4},
+		{"wait", 3, 1, 7},
+	}
+
+	for _, tt := range tests {
+		fn := findUnit(functions, tt.name)
+		if fn == nil || fn.Complexity == nil {
+			t.Errorf("%s: no complexity", tt.name)
+			continue
+		}
+		if got := *fn.Complexity; got != (Complexity{tt.cyclomatic, tt.nesting, tt.lines}) {
+			t.Errorf("%s: complexity = %+v, want {Cyclomatic:%d MaxNesting:%d Lines:%d}", tt.name, got, tt.cyclomatic, tt.nesting, tt.lines)
+		}
+	}
+
+	if fn := findUnit(functions, "Grade"); fn == nil || fn.Complexity != nil {
+		t.Errorf("Grade: struct should have no complexity")
+	}
+}
+
+func TestMeasureLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		python     bool
+		src        string
+		cyclomatic int
+		nesting    int
+	}{
+		{
+			name: "js branches and loops",
+			src: `function f(items) {
+  for (const item of items) {
+    if (item.ok && item.ready) {
+      continue
+    }
+  }
+  while (items.length || pending) {}
+}`,
+			cyclomatic: 6,
+			nesting:    2,
+		},
+		{
+			name: "js strings and comments",
+			src: `function f(a) {
+  log("if (a && b) { while }")
+  log('for (;;) || case')
+  log(` + "`catch ${a}`" + `)
+  // if (a || b) {
+  /* while (a) {
+     } for */ if (a) {}
+}`,
+			cyclomatic: 2,
+			nesting:    1,
+		},
+		{
+			name: "ternary needs spaces",
+			src: `function f(a, opts) {
+  const x = a ? 1 : 2
+  const y = a?1:2
+  const z = opts?.value
+}`,
+			cyclomatic: 2,
+			nesting:    0,
+		},
+		{
+			name: "ts optional parameter is no ternary",
+			src: `function f(a?: number, b ?: string) {
+  return a
+}`,
+			cyclomatic: 1,
+			nesting:    0,
+		},
+		{
+			name:   "python",
+			python: true,
+			src: `def f(a, b):
+    if a and b:
+        for x in b:
+            print("if x or y while")
+    # while True:
+    try:
+        pass
+    except ValueError:
+        pass
+    return [y for y in b if y]`,
+			cyclomatic: 7,
+			nesting:    2,
+		},
+		{
+			name:   "python operators are words",
+			python: true,
+			src: `def f(a):
+    return a && b || c ? d : e`,
+			cyclomatic: 1,
+			nesting:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(tt.src, "\n")
+			c := measureLines(lines, tt.python)
+
+			if c.Cyclomatic != tt.cyclomatic {
+				t.Errorf("cyclomatic = %d, want %d", c.Cyclomatic, tt.cyclomatic)
+			}
+			if c.MaxNesting != tt.nesting {
+				t.Errorf("nesting = %d, want %d", c.MaxNesting, tt.nesting)
+			}
+			if c.Lines != len(lines) {
+				t.Errorf("lines = %d, want %d", c.Lines, len(lines))
+			}
+		})
+	}
+}
+
+func TestMeasureComplexitySkipsMeasuredUnits(t *testing.T) {
+	src := "function f(a) {\n  if (a) {}\n}\nclass C {}\n"
+	measured := &Complexity{Cyclomatic: 9}
+	functions := []*Function{
+		{Name: "f", LineStart: 1, LineEnd: 3, Type: TypeFunction},
+		{Name: "g", LineStart: 1, LineEnd: 3, Type: TypeFunction, Complexity: measured},
+		{Name: "C", LineStart: 4, LineEnd: 4, Type: TypeStruct},
+		{Name: "out of range", LineStart: 3, LineEnd: 40, Type: TypeFunction},
+	}
+
+	MeasureComplexity(functions, []byte(src), LangJavaScript)
+
+	if c := functions[0].Complexity; c == nil || c.Cyclomatic != 2 {
+		t.Errorf("f: complexity = %+v, want cyclomatic 2", c)
+	}
+	if functions[1].Complexity != measured {
+		t.Errorf("g: a parser's own measurement was replaced")
+	}
+	if functions[2].Complexity != nil || functions[3].Complexity != nil {
+		t.Errorf("types and units outside the file should stay unmeasured")
+	}
+}
+
+func TestNotebookComplexity(t *testing.T) {
+	notebook := `{
+ "cells": [
+  {
+   "cell_type": "code",
+   "source": [
+    "import os\n",
+    "if os.name and os.sep:\n",
+    "    print(\"for while\")\n"
+   ]
+  },
+  {
+   "cell_type": "code",
+   "source": [
+    "def f(x):\n",
+    "    if x:\n",
+    "        for y in x:\n",
+    "            pass\n",
+    "    return 2\n",
+    "\n",
+    "f([1])"
+   ]
+  }
+ ],
+ "metadata": {"language_info": {"name": "python"}}
+}`
+
+	units, err := NewNotebookParser().Parse(strings.NewReader(notebook))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		cyclomatic int
+		nesting    int
+		lines      int
+	}{
+		{"cell[1]", 3, 1, 3},
+		// The cell counts the decisions of the function it defines
+		{"cell[2]", 3, 2, 7},
+		{"f", 3, 2, 6},
+	}
+
+	for _, tt := range tests {
+		fn := findUnit(units, tt.name)
+		if fn == nil || fn.Complexity == nil {
+			t.Errorf("%s: no complexity", tt.name)
+			continue
+		}
+		if got := *fn.Complexity; got != (Complexity{tt.cyclomatic, tt.nesting, tt.lines}) {
+			t.Errorf("%s: complexity = %+v, want {Cyclomatic:%d MaxNesting:%d Lines:%d}", tt.name, got, tt.cyclomatic, tt.nesting, tt.lines)
+		}
+	}
+}
+
+func findUnit(functions []*Function, name string) *Function {
+	for _, fn := range functions {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// LEARNING MOMENT: Single-File Components
+//
+// Vue and Svelte components mix markup, styles and code in one file:
+//
+//   <template>...</template>        # line 1
+//   <script setup lang="ts">        # line 5
+//   const count = ref(0)            # line 6  -> script line 1
+//   function increment() { ... }    # line 7  -> script line 2
+//   </script>
+//
+// Only the <script> blocks hold functions. We cut them out, hand them to the
+// JS/TS parser and shift the resulting line numbers back by the number of
+// lines that precede each block, so git diffs still map onto them.
+// Astro components add a frontmatter block fenced by "---" at the very top,
+// which is handled the same way.
+
+type ComponentParser struct {
+	script   *JSParser
+	openTag  *regexp.Regexp
+	closeTag *regexp.Regexp
+}
+
+func NewComponentParser() *ComponentParser {
+	return &ComponentParser{
+		script: NewJSParser(),
+		// <script>, <script setup>, <script lang="ts">, <script context="module">
+		openTag:  regexp.MustCompile(`(?i)<script\b[^>]*>`),
+		closeTag: regexp.MustCompile(`(?i)</script\s*>`),
+	}
+}
+
+func (cp *ComponentParser) Parse(reader io.Reader) ([]*Function, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	source := string(content)
+	functions := []*Function{}
+
+	offset := 0
+	if start, end, next, ok := frontmatter(source); ok {
+		blockFunctions, err := cp.parseBlock(source, start, end)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, blockFunctions...)
+		offset = next
+	}
+
+	for {
+		open := cp.openTag.FindStringIndex(source[offset:])
+		if open == nil {
+			break
+		}
+		blockStart := offset + open[1]
+
+		blockEnd := len(source)
+		next := len(source)
+		if close := cp.closeTag.FindStringIndex(source[blockStart:]); close != nil {
+			blockEnd = blockStart + close[0]
+			next = blockStart + close[1]
+		}
+
+		blockFunctions, err := cp.parseBlock(source, blockStart, blockEnd)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, blockFunctions...)
+
+		offset = next
+	}
+
+	return functions, nil
+}
+
+// parseBlock runs the JS/TS parser on source[start:end], in file lines
+func (cp *ComponentParser) parseBlock(source string, start, end int) ([]*Function, error) {
+	// Line 1 of the block is the file line start sits on (a <script> tag's line)
+	lineOffset := strings.Count(source[:start], "\n")
+
+	functions, err := cp.script.Parse(strings.NewReader(source[start:end]))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fn := range functions {
+		fn.LineStart += lineOffset
+		fn.LineEnd += lineOffset
+	}
+
+	return functions, nil
+}
+
+// frontmatter finds an Astro "---" fenced block on the first line: the code
+// between the fences and where the rest of the file starts
+func frontmatter(source string) (start, end, next int, ok bool) {
+	first, _, found := strings.Cut(source, "\n")
+	if !found || strings.TrimSpace(first) != "---" {
+		return 0, 0, 0, false
+	}
+
+	start = len(first) + 1
+	for offset := start; offset < len(source); {
+		line, _, _ := strings.Cut(source[offset:], "\n")
+		if strings.TrimSpace(line) == "---" {
+			return start, offset, min(offset+len(line)+1, len(source)), true
+		}
+		offset += len(line) + 1
+	}
+
+	return 0, 0, 0, false
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+type unitLines struct {
+	name       string
+	start, end int
+}
+
+func TestComponentParser(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []unitLines
+	}{
+		{
+			name: "vue script setup",
+			src: `<template>
+  <button @click="increment">{{ count }}</button>
+</template>
+
+<script setup lang="ts">
+import { ref } from 'vue'
+
+const count = ref(0)
+if (import.meta.env.DEV) {
+  console.log('dev')
+}
+for (const a of [1, 2]) {
+  console.log(a)
+}
+
+function increment() {
+  count.value++
+}
+</script>
+
+<style scoped>
+button { color: red; }
+</style>
+`,
+			want: []unitLines{{"increment", 16, 18}},
+		},
+		{
+			name: "vue options api",
+			src: `<template><div /></template>
+<script>
+export default {
+  methods: {
+    save() {
+      return 1
+    },
+  },
+}
+</script>
+`,
+			want: []unitLines{{"save", 5, 7}},
+		},
+		{
+			name: "svelte with module script",
+			src: `<script context="module">
+  export function preload() {
+    return {}
+  }
+</script>
+
+<script>
+  let items = []
+  while (items.length < 3) {
+    items.push(0)
+  }
+  switch (items.length) {
+    default:
+  }
+  const add = (x) => {
+    items = [...items, x]
+  }
+</script>
+
+<button on:click={() => add(1)}>add</button>
+`,
+			want: []unitLines{{"preload", 2, 4}, {"add", 15, 17}},
+		},
+		{
+			name: "astro frontmatter and script",
+			src: `---
+import Layout from '../layouts/Layout.astro'
+
+const posts = await getPosts()
+if (!posts.length) {
+  throw new Error('no posts')
+}
+
+function title(post) {
+  return post.data.title
+}
+---
+
+<Layout>
+  {posts.map((post) => <h2>{title(post)}</h2>)}
+</Layout>
+
+<script>
+  function toggle() {
+    document.body.classList.toggle('dark')
+  }
+</script>
+`,
+			want: []unitLines{{"title", 9, 11}, {"toggle", 19, 21}},
+		},
+		{
+			name: "astro without frontmatter",
+			src: `<div>---</div>
+<script>
+  function hello() {}
+</script>
+`,
+			want: []unitLines{{"hello", 3, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			functions, err := NewComponentParser().Parse(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []unitLines{}
+			for _, fn := range functions {
+				got = append(got, unitLines{fn.Name, fn.LineStart, fn.LineEnd})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("units = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("unit %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJSParserSkipsControlFlow(t *testing.T) {
+	jsp := NewJSParser()
+
+	for _, line := range []string{
+		"if (x) {",
+		"for (const a of x) {",
+		"while (x) {",
+		"switch (x) {",
+		"catch (err) {",
+		"with (obj) {",
+		"function (x) {",
+		"  return (x) {",
+	} {
+		if got := jsp.detectFunction(line); got != "" {
+			t.Errorf("detectFunction(%q) = %q, want no function", line, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -32,17 +33,21 @@ func (gp *GoParser) Parse(reader io.Reader) ([]*Function, error) {
 
 	functions := []*Function{}
 
-	ast.Inspect(astFileNode, func(n ast.Node) bool {
-		switch decl := n.(type) {
+	for _, d := range astFileNode.Decls {
+		switch decl := d.(type) {
 		case *ast.FuncDecl:
 			fn:= gp.extractFunction(fset, decl)
 
 			functions = append(functions, fn)
+
+			if decl.Body != nil {
+				functions = append(functions, gp.extractClosures(fset, decl.Body, fn.Name)...)
+			}
+		case *ast.GenDecl:
+			functions = append(functions, gp.extractGenDecl(fset, decl)...)
 		}
-		
-		return true //continue traversal
-	})
-	
+	}
+
 	return functions, nil
 }
 
@@ -53,34 +58,171 @@ func (gp *GoParser) extractFunction(fset *token.FileSet, decl *ast.FuncDecl) *Fu
 	endPos := fset.Position(decl.End())
 
 	fnType := TypeFunction
-	fnName := decl.Name.Name
-
-
 	if decl.Recv != nil {
 		// Has receiver = method
 		fnType = TypeMethod
-		// Include receiver in name: "(*User).GetName"
-		if len(decl.Recv.List) > 0 {
-			receiverType := getTypeName(decl.Recv.List[0].Type)
-			fnName = "(" + receiverType + ")." + fnName
-		}
 	}
 
 	return &Function{
-		Name: fnName,
+		Name: GoFuncName(decl),
 		LineStart: startPos.Line,
 		LineEnd: endPos.Line,
 		Type: fnType,
+		Complexity: goComplexity(fset, decl, decl.Body),
+	}
+}
+
+// GoFuncName names a function declaration the way GoParser reports it.
+// Methods include the receiver: "(*User).GetName"
+func GoFuncName(decl *ast.FuncDecl) string {
+	fnName := decl.Name.Name
+
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		receiverType := getTypeName(decl.Recv.List[0].Type)
+		fnName = "(" + receiverType + ")." + fnName
+	}
+
+	return fnName
+}
+
+// extractGenDecl emits struct/interface declarations as units and picks up
+// package-level function literals: var handler = func(...) {...}
+func (gp *GoParser) extractGenDecl(fset *token.FileSet, decl *ast.GenDecl) []*Function {
+	units := []*Function{}
+
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			var unitType FunctionType
+			switch s.Type.(type) {
+			case *ast.StructType:
+				unitType = TypeStruct
+			case *ast.InterfaceType:
+				unitType = TypeInterface
+			default:
+				continue
+			}
+
+			// Ungrouped declarations start at the "type" keyword
+			start := s.Pos()
+			if !decl.Lparen.IsValid() {
+				start = decl.Pos()
+			}
+
+			units = append(units, &Function{
+				Name:      s.Name.Name,
+				LineStart: fset.Position(start).Line,
+				LineEnd:   fset.Position(s.End()).Line,
+				Type:      unitType,
+			})
+		case *ast.ValueSpec:
+			for i, value := range s.Values {
+				if i >= len(s.Names) {
+					break
+				}
+				lit, ok := value.(*ast.FuncLit)
+				if !ok {
+					continue
+				}
+
+				closure := gp.newClosure(fset, lit, s.Names[i].Name)
+				units = append(units, closure)
+				units = append(units, gp.extractClosures(fset, lit.Body, closure.Name)...)
+			}
+		}
 	}
+
+	return units
 }
 
+// extractClosures finds function literals inside a body that are either
+// assigned to a variable or passed as an argument (e.g. HTTP handlers).
+//
+// Naming:
+// - handler := func() {}          -> "enclosing.handler"
+// - r.HandleFunc("/", func() {})  -> "enclosing.func1"
+func (gp *GoParser) extractClosures(fset *token.FileSet, body ast.Node, enclosing string) []*Function {
+	closures := []*Function{}
+	named := make(map[*ast.FuncLit]string)
+	anonymous := 0
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, rhs := range node.Rhs {
+				if lit, ok := rhs.(*ast.FuncLit); ok {
+					if ident, ok := node.Lhs[i].(*ast.Ident); ok && ident.Name != "_" {
+						named[lit] = enclosing + "." + ident.Name
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range node.Values {
+				if lit, ok := value.(*ast.FuncLit); ok && i < len(node.Names) && node.Names[i].Name != "_" {
+					named[lit] = enclosing + "." + node.Names[i].Name
+				}
+			}
+		case *ast.CallExpr:
+			for _, arg := range node.Args {
+				if lit, ok := arg.(*ast.FuncLit); ok {
+					if _, exists := named[lit]; !exists {
+						anonymous++
+						named[lit] = fmt.Sprintf("%s.func%d", enclosing, anonymous)
+					}
+				}
+			}
+		case *ast.FuncLit:
+			name, tracked := named[node]
+			if !tracked {
+				// Immediately invoked / deferred literals belong to the enclosing function
+				return true
+			}
+
+			closures = append(closures, gp.newClosure(fset, node, name))
+			closures = append(closures, gp.extractClosures(fset, node.Body, name)...)
+
+			return false // Body already handled with the closure as enclosing scope
+		}
+
+		return true
+	})
+
+	return closures
+}
+
+func (gp *GoParser) newClosure(fset *token.FileSet, lit *ast.FuncLit, name string) *Function {
+	return &Function{
+		Name:      name,
+		LineStart: fset.Position(lit.Pos()).Line,
+		LineEnd:   fset.Position(lit.End()).Line,
+		Type:      TypeClosure,
+		Complexity: goComplexity(fset, lit, lit.Body),
+	}
+}
+
+// getTypeName renders a receiver type. Type parameters are dropped so that
+// (*Cache[K, V]) and (*Cache[T, U]) both become "*Cache".
 func getTypeName(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.Ident:
 		return t.Name
 	case *ast.StarExpr:
 		return "*" + getTypeName(t.X)
+	case *ast.ParenExpr:
+		return getTypeName(t.X)
+	case *ast.SelectorExpr:
+		// Qualified type: pkg.Type
+		return getTypeName(t.X) + "." + t.Sel.Name
+	case *ast.IndexExpr:
+		// Generic type with one parameter: Cache[K]
+		return getTypeName(t.X)
+	case *ast.IndexListExpr:
+		// Generic type with several parameters: Cache[K, V]
+		return getTypeName(t.X)
 	default:
 		return "unknown"
 	}
-}
\ No newline at end of file
+}
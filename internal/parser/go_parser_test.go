@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoParserUnits(t *testing.T) {
+	src := `package cache
+
+import "net/http"
+
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+type (
+	Getter interface {
+		Get(key string) string
+	}
+
+	Pair[K comparable, V any] struct {
+		Key   K
+		Value V
+	}
+
+	ID int
+)
+
+func (c *Cache[K, V]) Get(key K) V {
+	return c.items[key]
+}
+
+func (c Cache[K, V]) Len() int { return len(c.items) }
+
+func (p *Pair[K, V]) Swap() {}
+
+func (s *http.Server) Broken() {}
+
+func Register(mux *http.ServeMux) {
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	handler := func() {
+		defer func() {}()
+		go run(func() {})
+	}
+	handler()
+
+	var logger = func(msg string) {}
+	logger("x")
+
+	_ = func() {}
+	func() {}()
+}
+
+var Default = func() *Cache[string, int] {
+	setup := func() {}
+	setup()
+	return nil
+}
+`
+
+	functions, err := NewGoParser().Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name       string
+		unitType   FunctionType
+		start, end int
+	}{
+		{"Cache", TypeStruct, 5, 7},
+		{"Getter", TypeInterface, 10, 12},
+		{"Pair", TypeStruct, 14, 17},
+		{"(*Cache).Get", TypeMethod, 22, 24},
+		{"(Cache).Len", TypeMethod, 26, 26},
+		{"(*Pair).Swap", TypeMethod, 28, 28},
+		{"(*http.Server).Broken", TypeMethod, 30, 30},
+		{"Register", TypeFunction, 32, 48},
+		{"Register.func1", TypeClosure, 33, 35},
+		{"Register.func1.func1", TypeClosure, 34, 34},
+		{"Register.handler", TypeClosure, 37, 40},
+		{"Register.handler.func1", TypeClosure, 39, 39},
+		{"Register.logger", TypeClosure, 43, 43},
+		{"Default", TypeClosure, 50, 54},
+		{"Default.setup", TypeClosure, 51, 51},
+	}
+
+	got := make(map[string]*Function)
+	for _, fn := range functions {
+		if _, dup := got[fn.Name]; dup {
+			t.Errorf("unit %q reported twice", fn.Name)
+		}
+		got[fn.Name] = fn
+	}
+	if len(functions) != len(want) {
+		names := []string{}
+		for _, fn := range functions {
+			names = append(names, fn.Name)
+		}
+		t.Errorf("units = %v, want %d of them", names, len(want))
+	}
+
+	for _, w := range want {
+		fn := got[w.name]
+		if fn == nil {
+			t.Errorf("%s: missing", w.name)
+			continue
+		}
+		if fn.Type != w.unitType || fn.LineStart != w.start || fn.LineEnd != w.end {
+			t.Errorf("%s: %s lines %d-%d, want %s lines %d-%d", w.name, fn.Type, fn.LineStart, fn.LineEnd, w.unitType, w.start, w.end)
+		}
+	}
+}
+
+func TestGoParserSyntaxError(t *testing.T) {
+	functions, err := NewGoParser().Parse(strings.NewReader("package x\n\nfunc broken( {\n"))
+	if err != nil || len(functions) != 0 {
+		t.Errorf("Parse = %v, %v, want no units and no error", functions, err)
+	}
+}
@@ -22,24 +22,36 @@ import (
 // - class methods: methodName() {}
 // - async function foo() {}
 
+// Keywords a pattern can pick up as a name, e.g. "async (x) {" or the
+// top-level control flow of a module or <script setup>: "if (x) {"
+var jsKeywords = map[string]bool{
+	"async": true, "export": true, "default": true,
+	"const": true, "let": true, "var": true,
+	"if": true, "else": true, "for": true, "while": true, "switch": true,
+	"catch": true, "with": true, "return": true, "function": true,
+}
+
 type JSParser struct {
 	patterns []*regexp.Regexp
 }
 
 func NewJSParser() *JSParser {
-	// Compile regex patterns for different function styles
+	// Compile regex patterns for different function styles.
+	// Every pattern captures the function name in the "name" group.
 	patterns := []*regexp.Regexp{
 		// function declaration: function foo() { or async function foo() {
-		regexp.MustCompile(`(?m)^[\s]*(async\s+)?function\s+(\w+)\s*\(`),
+		// also exported: export function foo() { / export default async function foo() {
+		// and generators: function* foo() {
+		regexp.MustCompile(`(?m)^[\s]*(export\s+)?(default\s+)?(async\s+)?\bfunction\b\s*\*?\s*(?P<name>\w+)\s*\(`),
 
 		// arrow functions: const foo = () => { or export const foo = async () => {
-		regexp.MustCompile(`(?m)^[\s]*(export\s+)?(const|let|var)\s+(\w+)\s*=\s*(async\s*)?\([^)]*\)\s*=>`),
+		regexp.MustCompile(`(?m)^[\s]*(export\s+)?(const|let|var)\s+(?P<name>\w+)\s*=\s*(async\s*)?\([^)]*\)\s*=>`),
 
 		// class methods: methodName() { or async methodName() {
-		regexp.MustCompile(`(?m)^[\s]*(async\s+)?(\w+)\s*\([^)]*\)\s*\{`),
+		regexp.MustCompile(`(?m)^[\s]*(async\s+)?(?P<name>\w+)\s*\([^)]*\)\s*\{`),
 
 		// object method shorthand: foo() { inside objects
-		regexp.MustCompile(`(?m)^\s*(\w+)\s*\([^)]*\)\s*\{`),
+		regexp.MustCompile(`(?m)^\s*(?P<name>\w+)\s*\([^)]*\)\s*\{`),
 	}
 
 	return &JSParser{patterns: patterns}
@@ -102,18 +114,14 @@ func (jsp *JSParser) detectFunction(line string) string {
 	// Try each pattern
 	for _, pattern := range jsp.patterns {
 		matches := pattern.FindStringSubmatch(line)
-		if len(matches) >= 3 {
-			// Extract function name from capture groups
-			// Different patterns have name in different positions
-			for i := len(matches) - 1; i >= 2; i-- {
-				if matches[i] != "" && !strings.Contains(matches[i], "async") &&
-					!strings.Contains(matches[i], "export") &&
-					!strings.Contains(matches[i], "const") &&
-					!strings.Contains(matches[i], "let") &&
-					!strings.Contains(matches[i], "var") {
-					return matches[i]
-				}
-			}
+		if matches == nil {
+			continue
+		}
+
+		// Keywords sit in groups of their own, so the name group is the name
+		// even when it contains one ("defaultHandler", "variance")
+		if name := matches[pattern.SubexpIndex("name")]; name != "" && !jsKeywords[name] {
+			return name
 		}
 	}
 	return ""
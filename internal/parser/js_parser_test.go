@@ -0,0 +1,29 @@
+package parser
+
+import "testing"
+
+func TestJSParserDetectFunction(t *testing.T) {
+	jsp := NewJSParser()
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"function foo() {", "foo"},
+		{"export default async function handler(req) {", "handler"},
+		{"export default function defaultHandler() {", "defaultHandler"},
+		{"function* items() {", "items"},
+		{"function *items() {", "items"},
+		{"  functionalUpdate(x) {", "functionalUpdate"},
+		{"export const variance = (xs) => {", "variance"},
+		{"const letter = async () => {", "letter"},
+		{"  async fetchAll() {", "fetchAll"},
+		{"  async (x) {", ""},
+	}
+
+	for _, tt := range tests {
+		if got := jsp.detectFunction(tt.line); got != tt.want {
+			t.Errorf("detectFunction(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -14,6 +15,17 @@ const (
 	LangPython     Language = "python"
 	LangJava       Language = "java"
 	LangRust       Language = "rust"
+	LangC          Language = "c"
+	LangCPP        Language = "cpp"
+	LangRuby       Language = "ruby"
+	LangPerl       Language = "perl"
+	LangShell      Language = "shell"
+	LangDockerfile Language = "dockerfile"
+	LangMakefile   Language = "makefile"
+	LangVue        Language = "vue"
+	LangSvelte     Language = "svelte"
+	LangAstro      Language = "astro"
+	LangJupyter    Language = "jupyter"
 	LangUnknown    Language = "unknown"
 )
 
@@ -37,16 +49,120 @@ var extensionToLanguage = map[string]Language{
 	".pyw":  LangPython,
 	".pyi":  LangPython,
 
+	// Jupyter notebooks (JSON wrapping Python cells)
+	".ipynb": LangJupyter,
+
+	// Single-file components (script blocks are JS/TS)
+	".vue":    LangVue,
+	".svelte": LangSvelte,
+	".astro":  LangAstro,
+
 	// Java
 	".java": LangJava,
 
 	// Rust
 	".rs": LangRust,
+
+	// C / C++ (".h" is resolved from content, see detectHeader)
+	".c":   LangC,
+	".h":   LangC,
+	".cc":  LangCPP,
+	".cpp": LangCPP,
+	".cxx": LangCPP,
+	".hh":  LangCPP,
+	".hpp": LangCPP,
+	".hxx": LangCPP,
+
+	// Scripting
+	".rb":   LangRuby,
+	".pl":   LangPerl,
+	".pm":   LangPerl,
+	".sh":   LangShell,
+	".bash": LangShell,
+	".zsh":  LangShell,
+
+	// Build files
+	".mk":         LangMakefile,
+	".dockerfile": LangDockerfile,
 }
 
+// Well-known files without a (meaningful) extension
+var filenameToLanguage = map[string]Language{
+	"dockerfile":    LangDockerfile,
+	"containerfile": LangDockerfile,
+	"makefile":      LangMakefile,
+	"gnumakefile":   LangMakefile,
+	"rakefile":      LangRuby,
+	"gemfile":       LangRuby,
+	".bashrc":       LangShell,
+	".bash_profile": LangShell,
+	".zshrc":        LangShell,
+	".profile":      LangShell,
+}
+
+// Names used by shebang interpreters, modelines and linguist-language values
+var aliasToLanguage = map[string]Language{
+	"go":         LangGo,
+	"golang":     LangGo,
+	"javascript": LangJavaScript,
+	"js":         LangJavaScript,
+	"node":       LangJavaScript,
+	"nodejs":     LangJavaScript,
+	"deno":       LangTypeScript,
+	"typescript": LangTypeScript,
+	"ts":         LangTypeScript,
+	"ts-node":    LangTypeScript,
+	"vue":        LangVue,
+	"svelte":     LangSvelte,
+	"astro":      LangAstro,
+	"python":     LangPython,
+	"py":         LangPython,
+	"java":       LangJava,
+	"rust":       LangRust,
+	"c":          LangC,
+	"c++":        LangCPP,
+	"cpp":        LangCPP,
+	"ruby":       LangRuby,
+	"rb":         LangRuby,
+	"perl":       LangPerl,
+	"shell":      LangShell,
+	"sh":         LangShell,
+	"bash":       LangShell,
+	"zsh":        LangShell,
+	"dash":       LangShell,
+	"ksh":        LangShell,
+	"dockerfile": LangDockerfile,
+	"docker":     LangDockerfile,
+	"makefile":   LangMakefile,
+	"make":       LangMakefile,
+}
+
+var (
+	// vim: set ft=python:  /  vi: filetype=ruby
+	vimModeline = regexp.MustCompile(`(?:^|\s)(?:vi|vim|ex)(?:[<=>]?\d+)?:.*?\b(?:ft|filetype|syntax)=([\w+-]+)`)
+
+	// -*- mode: python; coding: utf-8 -*-  /  -*- python -*-
+	emacsModeline = regexp.MustCompile(`-\*-(.*?)-\*-`)
+	emacsMode     = regexp.MustCompile(`^[\w+-]+$`)
+
+	// Constructs that only appear in C++ headers
+	cppHeaderHint = regexp.MustCompile(`(?m)^\s*(?:class|namespace|template\s*<|using\s+namespace)\b|std::|^\s*(?:public|private|protected):`)
+
+	interpreterVersion = regexp.MustCompile(`[\d.]+$`)
+)
+
+// modelineScanLines is how many lines at the top and bottom of a file are
+// checked for editor modelines (vim checks 5 by default)
+const modelineScanLines = 5
+
+// DetectLanguage resolves a language from the path alone (filename, then extension)
 func DetectLanguage(filePath string) Language {
+	if lang := detectFilename(filePath); lang != LangUnknown {
+		return lang
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
-	
+
 	if lang, exists := extensionToLanguage[ext]; exists {
 		return lang
 	}
@@ -54,8 +170,145 @@ func DetectLanguage(filePath string) Language {
 	return LangUnknown
 }
 
+// DetectLanguageFromContent also inspects the file content.
+// Precedence (same as GitHub linguist): modeline > filename > shebang > extension
+func DetectLanguageFromContent(filePath string, content []byte) Language {
+	if lang := detectModeline(content); lang != LangUnknown {
+		return lang
+	}
+
+	if lang := detectFilename(filePath); lang != LangUnknown {
+		return lang
+	}
+
+	if lang := detectShebang(content); lang != LangUnknown {
+		return lang
+	}
+
+	// ".h" is shared by C and C++ - needs a look at the content
+	if strings.EqualFold(filepath.Ext(filePath), ".h") {
+		return detectHeader(content)
+	}
+
+	return DetectLanguage(filePath)
+}
+
+// LanguageFromName maps a human/tool name ("Python", "C++", "node") to a Language
+func LanguageFromName(name string) Language {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if lang, exists := aliasToLanguage[name]; exists {
+		return lang
+	}
+
+	// python3, python3.11, ruby2.7
+	if lang, exists := aliasToLanguage[interpreterVersion.ReplaceAllString(name, "")]; exists {
+		return lang
+	}
+
+	return LangUnknown
+}
+
+func detectFilename(filePath string) Language {
+	name := strings.ToLower(filepath.Base(filePath))
+	if lang, exists := filenameToLanguage[name]; exists {
+		return lang
+	}
+
+	// Dockerfile.dev, Makefile.linux, ...
+	stem, _, _ := strings.Cut(name, ".")
+	if lang := filenameToLanguage[stem]; lang == LangDockerfile || lang == LangMakefile {
+		return lang
+	}
+
+	return LangUnknown
+}
+
+func detectShebang(content []byte) Language {
+	if len(content) < 2 || content[0] != '#' || content[1] != '!' {
+		return LangUnknown
+	}
+
+	firstLine, _, _ := strings.Cut(string(content[2:]), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 {
+		return LangUnknown
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// #!/usr/bin/env -S node --flag  ->  skip options and VAR=value pairs
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+				continue
+			}
+			interpreter = filepath.Base(field)
+			break
+		}
+	}
+
+	return LanguageFromName(interpreter)
+}
+
+func detectModeline(content []byte) Language {
+	lines := strings.Split(string(content), "\n")
+
+	candidates := lines
+	if len(lines) > 2*modelineScanLines {
+		candidates = append(lines[:modelineScanLines:modelineScanLines], lines[len(lines)-modelineScanLines:]...)
+	}
+
+	for _, line := range candidates {
+		if matches := vimModeline.FindStringSubmatch(line); matches != nil {
+			if lang := LanguageFromName(matches[1]); lang != LangUnknown {
+				return lang
+			}
+		}
+		if matches := emacsModeline.FindStringSubmatch(line); matches != nil {
+			if lang := LanguageFromName(emacsModelineMode(matches[1])); lang != LangUnknown {
+				return lang
+			}
+		}
+	}
+
+	return LangUnknown
+}
+
+// emacsModelineMode reads the mode between the -*- markers: the "mode"
+// variable of a "var: value; ..." list, or the whole thing when it's one word
+func emacsModelineMode(vars string) string {
+	if !strings.Contains(vars, ":") {
+		if mode := strings.TrimSpace(vars); emacsMode.MatchString(mode) {
+			return mode
+		}
+		return ""
+	}
+
+	for _, pair := range strings.Split(vars, ";") {
+		key, value, ok := strings.Cut(pair, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "mode") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// detectHeader disambiguates ".h" files between C and C++
+func detectHeader(content []byte) Language {
+	if cppHeaderHint.Match(content) {
+		return LangCPP
+	}
+	return LangC
+}
+
 func IsSupported(lang Language) bool {
-	return lang != LangUnknown
+	switch lang {
+	case LangUnknown, LangDockerfile, LangMakefile:
+		// Build files have no function-like units to track
+		return false
+	default:
+		return true
+	}
 }
 
 // GetParser returns the appropriate parser for a language
@@ -65,6 +318,10 @@ func GetParser(lang Language) Parser {
 		return NewGoParser()
 	case LangJavaScript, LangTypeScript:
 		return NewJSParser()
+	case LangVue, LangSvelte, LangAstro:
+		return NewComponentParser()
+	case LangJupyter:
+		return NewNotebookParser()
 	case LangPython:
 		return NewPythonParser()
 	default:
@@ -0,0 +1,46 @@
+package parser
+
+import "testing"
+
+func TestDetectModeline(t *testing.T) {
+	tests := []struct {
+		line string
+		want Language
+	}{
+		{"# -*- mode: python; coding: utf-8 -*-", LangPython},
+		{"# -*- coding: utf-8; mode: ruby -*-", LangRuby},
+		{"// -*- Mode: C++; tab-width: 4 -*-", LangCPP},
+		{"# -*- python -*-", LangPython},
+		{"# -*- coding: utf-8 -*-", LangUnknown},
+		{"# vim: set ft=python:", LangPython},
+		{"print('no modeline')", LangUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := detectModeline([]byte(tt.line + "\n")); got != tt.want {
+			t.Errorf("detectModeline(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestDetectFilename(t *testing.T) {
+	tests := []struct {
+		path string
+		want Language
+	}{
+		{"Dockerfile", LangDockerfile},
+		{"build/Dockerfile.prod", LangDockerfile},
+		{"GNUmakefile", LangMakefile},
+		{"Gemfile", LangRuby},
+		{"home/.zshrc", LangShell},
+		// Groovy has no parser; better undetected than parsed as Java
+		{"Jenkinsfile", LangUnknown},
+		{"README", LangUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := detectFilename(tt.path); got != tt.want {
+			t.Errorf("detectFilename(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
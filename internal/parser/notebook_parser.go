@@ -0,0 +1,262 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// LEARNING MOMENT: Notebooks Are JSON, Diffs Are Lines
+//
+// A .ipynb file stores each cell's source as a JSON array with one string
+// per source line (nbformat 4):
+//
+//   "cells": [
+//     {
+//       "cell_type": "code",
+//       "source": [
+//         "def load(path):\n",      <- JSON line 7  = cell 1, line 1
+//         "    return read(path)"   <- JSON line 8  = cell 1, line 2
+//       ]
+//     },
+//
+// Git only sees the JSON, so a diff says "line 8 changed". We record which
+// JSON line holds each source line, and report every unit twice:
+// - LineStart/LineEnd in JSON coordinates -> git changes map onto them
+// - Cell/CellLineStart/CellLineEnd        -> what the user actually sees
+
+type NotebookParser struct {
+	python *PythonParser
+}
+
+func NewNotebookParser() *NotebookParser {
+	return &NotebookParser{python: NewPythonParser()}
+}
+
+// notebookCell is a code cell with the JSON line of every source line
+type notebookCell struct {
+	index     int // 1-based position among all cells
+	lines     []string
+	jsonLines []int
+}
+
+func (np *NotebookParser) Parse(reader io.Reader) ([]*Function, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	cells, kernel, err := np.readCells(content)
+	if err != nil {
+		// Malformed notebook - return empty (don't fail entire analysis)
+		return []*Function{}, nil
+	}
+
+	units := []*Function{}
+
+	for _, cell := range cells {
+		if len(cell.lines) == 0 {
+			continue
+		}
+
+		// Measured on the cell source here, the JSON lines would only get in the way
+		units = append(units, &Function{
+			Name:          fmt.Sprintf("cell[%d]", cell.index),
+			LineStart:     cell.jsonLines[0],
+			LineEnd:       cell.jsonLines[len(cell.jsonLines)-1],
+			Type:          TypeCell,
+			Cell:          cell.index,
+			CellLineStart: 1,
+			CellLineEnd:   len(cell.lines),
+			Complexity:    measureLines(cell.lines, kernel == LangPython),
+		})
+
+		// Function detection only makes sense for Python kernels
+		if kernel != LangPython {
+			continue
+		}
+
+		functions, err := np.python.Parse(strings.NewReader(strings.Join(cell.lines, "\n")))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fn := range functions {
+			fn.Cell = cell.index
+			fn.CellLineStart = fn.LineStart
+			fn.CellLineEnd = fn.LineEnd
+			fn.Complexity = measureLines(cell.lines[fn.CellLineStart-1:fn.CellLineEnd], true)
+			fn.LineStart = cell.jsonLines[fn.CellLineStart-1]
+			fn.LineEnd = cell.jsonLines[fn.CellLineEnd-1]
+			units = append(units, fn)
+		}
+	}
+
+	return units, nil
+}
+
+// readCells walks the notebook with a streaming decoder so that every
+// source string can be tied back to the JSON line it was read from
+func (np *NotebookParser) readCells(content []byte) ([]*notebookCell, Language, error) {
+	lineStarts := []int{0}
+	for i, b := range content {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	lineAt := func(offset int64) int {
+		return sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > int(offset) })
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(content)))
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, "", err
+	}
+
+	cells := []*notebookCell{}
+	kernel := LangPython // nbformat default when metadata is missing
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, "", err
+		}
+
+		switch key {
+		case "cells":
+			if cells, err = np.readCellArray(dec, lineAt); err != nil {
+				return nil, "", err
+			}
+		case "metadata":
+			var metadata struct {
+				Kernelspec struct {
+					Language string `json:"language"`
+				} `json:"kernelspec"`
+				LanguageInfo struct {
+					Name string `json:"name"`
+				} `json:"language_info"`
+			}
+			if err := dec.Decode(&metadata); err != nil {
+				return nil, "", err
+			}
+			if name := metadata.LanguageInfo.Name; name != "" {
+				kernel = LanguageFromName(name)
+			} else if name := metadata.Kernelspec.Language; name != "" {
+				kernel = LanguageFromName(name)
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	return cells, kernel, nil
+}
+
+func (np *NotebookParser) readCellArray(dec *json.Decoder, lineAt func(int64) int) ([]*notebookCell, error) {
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, err
+	}
+
+	cells := []*notebookCell{}
+	index := 0
+
+	for dec.More() {
+		index++
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+
+		cellType := ""
+		cell := &notebookCell{index: index}
+
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			switch key {
+			case "cell_type":
+				if err := dec.Decode(&cellType); err != nil {
+					return nil, err
+				}
+			case "source":
+				if err := readSource(dec, lineAt, cell); err != nil {
+					return nil, err
+				}
+			default:
+				var skip json.RawMessage
+				if err := dec.Decode(&skip); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := expectDelim(dec, '}'); err != nil {
+			return nil, err
+		}
+
+		// Markdown and raw cells carry no code
+		if cellType == "code" {
+			cells = append(cells, cell)
+		}
+	}
+
+	return cells, expectDelim(dec, ']')
+}
+
+// readSource accepts both the list form and the (older) single-string form
+func readSource(dec *json.Decoder, lineAt func(int64) int, cell *notebookCell) error {
+	addChunk := func(chunk string, jsonLine int) {
+		for _, line := range strings.SplitAfter(chunk, "\n") {
+			if line == "" {
+				continue
+			}
+			cell.lines = append(cell.lines, strings.TrimSuffix(line, "\n"))
+			cell.jsonLines = append(cell.jsonLines, jsonLine)
+		}
+	}
+
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if chunk, ok := token.(string); ok {
+		addChunk(chunk, lineAt(dec.InputOffset()))
+		return nil
+	}
+
+	if token != json.Delim('[') {
+		return fmt.Errorf("unexpected source token %v", token)
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if chunk, ok := token.(string); ok {
+			// InputOffset is just past the string, still on its line
+			addChunk(chunk, lineAt(dec.InputOffset()-1))
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// notebookUnit is what a notebook unit reports in both coordinate systems
+type notebookUnit struct {
+	name               string
+	unitType           FunctionType
+	cell               int
+	cellStart, cellEnd int
+	jsonStart, jsonEnd int
+}
+
+func notebookUnits(t *testing.T, notebook string) []notebookUnit {
+	t.Helper()
+
+	functions, err := NewNotebookParser().Parse(strings.NewReader(notebook))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	units := []notebookUnit{}
+	for _, fn := range functions {
+		units = append(units, notebookUnit{fn.Name, fn.Type, fn.Cell, fn.CellLineStart, fn.CellLineEnd, fn.LineStart, fn.LineEnd})
+	}
+	return units
+}
+
+func TestNotebookParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		notebook string
+		want     []notebookUnit
+	}{
+		{
+			name: "list source, markdown and raw cells skipped",
+			notebook: `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "source": ["# Title\n", "def not_code():\n"]
+  },
+  {
+   "cell_type": "code",
+   "metadata": {},
+   "source": [
+    "import os\n",
+    "\n",
+    "def load(path):\n",
+    "    return open(path).read()\n"
+   ]
+  },
+  {
+   "cell_type": "raw",
+   "source": ["def raw():\n"]
+  },
+  {
+   "cell_type": "code",
+   "source": [
+    "data = load('x')"
+   ]
+  }
+ ],
+ "metadata": {},
+ "nbformat": 4
+}`,
+			want: []notebookUnit{
+				{"cell[2]", TypeCell, 2, 1, 4, 11, 14},
+				{"load", TypeFunction, 2, 3, 4, 13, 14},
+				{"cell[4]", TypeCell, 4, 1, 1, 24, 24},
+			},
+		},
+		{
+			name: "single string source",
+			notebook: `{
+ "cells": [
+  {
+   "cell_type": "code",
+   "source": "def f():\n    return 1\n\nf()"
+  }
+ ]
+}`,
+			want: []notebookUnit{
+				{"cell[1]", TypeCell, 1, 1, 4, 5, 5},
+				// The whole source sits on one JSON line
+				{"f", TypeFunction, 1, 1, 3, 5, 5},
+			},
+		},
+		{
+			name: "several lines in one list entry",
+			notebook: `{
+ "cells": [
+  {
+   "cell_type": "code",
+   "source": [
+    "def f():\n    return 1\n",
+    "f()"
+   ]
+  }
+ ]
+}`,
+			want: []notebookUnit{
+				{"cell[1]", TypeCell, 1, 1, 3, 6, 7},
+				{"f", TypeFunction, 1, 1, 2, 6, 6},
+			},
+		},
+		{
+			name: "non-Python kernel has cells only",
+			notebook: `{
+ "cells": [
+  {
+   "cell_type": "code",
+   "source": ["function f(x)\n", "    x + 1\n", "end"]
+  }
+ ],
+ "metadata": {"kernelspec": {"language": "julia"}}
+}`,
+			want: []notebookUnit{
+				{"cell[1]", TypeCell, 1, 1, 3, 5, 5},
+			},
+		},
+		{
+			name: "empty cells are skipped",
+			notebook: `{
+ "cells": [
+  {"cell_type": "code", "source": []},
+  {"cell_type": "code", "source": ""},
+  {"cell_type": "code", "source": ["x = 1"]}
+ ]
+}`,
+			want: []notebookUnit{
+				{"cell[3]", TypeCell, 3, 1, 1, 5, 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := notebookUnits(t, tt.notebook)
+			if len(got) != len(tt.want) {
+				t.Fatalf("units = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("unit %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNotebookParserMalformed(t *testing.T) {
+	for _, notebook := range []string{
+		``,
+		`not json`,
+		`{"cells": [{"cell_type": "code", "source": ["x = 1"]`,
+		`{"cells": {"cell_type": "code"}}`,
+		`{"cells": [{"cell_type": "code", "source": 42}]}`,
+		`[]`,
+	} {
+		functions, err := NewNotebookParser().Parse(strings.NewReader(notebook))
+		if err != nil || len(functions) != 0 {
+			t.Errorf("Parse(%q) = %d units, %v, want none and no error", notebook, len(functions), err)
+		}
+	}
+}
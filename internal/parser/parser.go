@@ -1,6 +1,9 @@
 package parser
 
-import "io"
+import (
+	"io"
+	"sort"
+)
 
 // Parser extracts function definitions from source code
 type Parser interface {
@@ -15,6 +18,19 @@ type Function struct {
 	LineStart int
 	LineEnd   int
 	Type      FunctionType // function, method, closure, etc.
+
+	// Notebook units only: 1-based cell index and lines within that cell
+	// (LineStart/LineEnd then hold JSON file lines, see NotebookParser)
+	Cell          int
+	CellLineStart int
+	CellLineEnd   int
+
+	// firesight:ignore / firesight:keep comment on or above the unit, see Annotate
+	Annotation     Annotation
+	AnnotationNote string
+
+	// nil for type declarations, see MeasureComplexity
+	Complexity *Complexity
 }
 
 type FunctionType string
@@ -23,6 +39,13 @@ const (
 	TypeFunction FunctionType = "function"
 	TypeMethod   FunctionType = "method"
 	TypeClosure  FunctionType = "closure"
+
+	// Type declarations tracked as units so field changes get heat too
+	TypeStruct    FunctionType = "struct"
+	TypeInterface FunctionType = "interface"
+
+	// Notebook code cell
+	TypeCell FunctionType = "cell"
 )
 
 // LEARNING MOMENT: Interval Tree Concept
@@ -33,53 +56,66 @@ const (
 // Why not a tree? For our use case, sorted array is simpler and fast enough.
 //
 // Algorithm:
-// 1. Sort functions by LineStart
-// 2. Binary search to find function where: LineStart <= line <= LineEnd
+// 1. Sort functions by LineStart (wider first on a tie, so the inner unit
+//    comes later), and keep the running maximum of LineEnd
+// 2. Binary search for the last function with LineStart <= line
+// 3. Walk back to the first one that still covers the line; stop as soon as
+//    the running maximum says no earlier unit reaches it
 
 // FunctionMap provides O(log n) lookup: line number → function
 type FunctionMap struct {
-	functions []*Function // Sorted by LineStart
+	functions []*Function // Sorted by LineStart, then LineEnd descending
+	maxEnd    []int       // maxEnd[i]: largest LineEnd among functions[:i+1]
 }
 
 // NewFunctionMap creates a searchable map of functions
 func NewFunctionMap(functions []*Function) *FunctionMap {
-	// Sort by line start (ascending)
-	// We don't need a sorting algorithm here - Go's sort.Slice does it
-
 	sorted := make([]*Function, len(functions))
 	copy(sorted, functions)
 
-	// Bubble sort for educational purposes
-	// TODO: replace with sort.Slice in production
-	for i := 0; i < len(sorted); i++ {
-		for j := i + 1; j < len(sorted); j++ {
-			if sorted[i].LineStart > sorted[j].LineStart {
-				sorted[i], sorted[j] = sorted[j], sorted[i]
-			}
+	// Units sharing a start line (a notebook cell and its first function)
+	// nest, so the narrower one sorts later and wins the backward walk
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].LineStart != sorted[j].LineStart {
+			return sorted[i].LineStart < sorted[j].LineStart
+		}
+		return sorted[i].LineEnd > sorted[j].LineEnd
+	})
+
+	maxEnd := make([]int, len(sorted))
+	for i, fn := range sorted {
+		maxEnd[i] = fn.LineEnd
+		if i > 0 && maxEnd[i-1] > fn.LineEnd {
+			maxEnd[i] = maxEnd[i-1]
 		}
 	}
 
-	return &FunctionMap{functions: sorted}
+	return &FunctionMap{functions: sorted, maxEnd: maxEnd}
 }
 
-// FindByLine returns the function containing the given line number
-// Time Complexity: O(log n) - Binary search
+// FindByLine returns the innermost function containing the given line number
+// Time Complexity: O(log n) binary search, plus a backward walk over the
+// units between the innermost enclosing one and the line. Lines outside
+// every unit stop right away.
 func (fm *FunctionMap) FindByLine(line int) *Function {
-	// Binary search for the function containing this line
+	// Binary search for the last function starting at or before this line
 	left, right := 0, len(fm.functions)-1
+	candidate := -1
 
 	for left <= right {
 		mid := (left + right) / 2
-		fn := fm.functions[mid]
-
-		if line < fn.LineStart {
-
-			right = mid - 1
-		} else if line > fn.LineEnd {
 
+		if fm.functions[mid].LineStart <= line {
+			candidate = mid
 			left = mid + 1
 		} else {
+			right = mid - 1
+		}
+	}
 
+	// Walk back: the first interval that still covers the line is the innermost
+	for i := candidate; i >= 0 && fm.maxEnd[i] >= line; i-- {
+		if fn := fm.functions[i]; line <= fn.LineEnd {
 			return fn
 		}
 	}
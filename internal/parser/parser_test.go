@@ -0,0 +1,48 @@
+package parser
+
+import "testing"
+
+func TestFunctionMapFindByLine(t *testing.T) {
+	outer := &Function{Name: "outer", LineStart: 1, LineEnd: 20}
+	closure := &Function{Name: "closure", LineStart: 5, LineEnd: 8}
+	cell := &Function{Name: "cell", LineStart: 30, LineEnd: 50, Type: TypeCell}
+	inCell := &Function{Name: "inCell", LineStart: 30, LineEnd: 35}
+	after := &Function{Name: "after", LineStart: 40, LineEnd: 45}
+
+	tests := []struct {
+		line int
+		want *Function
+	}{
+		{1, outer},
+		{6, closure},
+		{10, outer},
+		{25, nil},
+		{30, inCell},
+		{37, cell},
+		{42, after},
+		{60, nil},
+	}
+
+	// The result must not depend on the input order
+	orders := [][]*Function{
+		{outer, closure, cell, inCell, after},
+		{after, inCell, cell, closure, outer},
+		{cell, inCell, outer, after, closure},
+	}
+
+	for _, order := range orders {
+		fm := NewFunctionMap(order)
+		for _, tt := range tests {
+			if got := fm.FindByLine(tt.line); got != tt.want {
+				t.Errorf("FindByLine(%d) = %v, want %v", tt.line, unitName(got), unitName(tt.want))
+			}
+		}
+	}
+}
+
+func unitName(fn *Function) string {
+	if fn == nil {
+		return "<nil>"
+	}
+	return fn.Name
+}
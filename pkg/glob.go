@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Glob is a compiled gitignore-style path pattern
+//
+// Supported syntax:
+// - "*" and "?" match within a single path segment
+// - "**" matches across segments ("vendor/**", "**/testdata/*")
+// - [abc] / [!abc] character classes
+// - a pattern without a slash matches the basename at any depth
+// - a leading "/" anchors the pattern to the repository root
+// - a trailing "/" only matches directories (i.e. everything inside them)
+type Glob struct {
+	Pattern string
+	re      *regexp.Regexp
+}
+
+func CompileGlob(pattern string) (*Glob, error) {
+	re, err := regexp.Compile(globToRegexp(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Glob{Pattern: pattern, re: re}, nil
+}
+
+// Match reports whether the slash-separated path matches the pattern
+func (g *Glob) Match(path string) bool {
+	return g.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// MatchOrParent also matches when one of the path's parent directories
+// matches, which is how .gitignore treats directory patterns
+func (g *Glob) MatchOrParent(path string) bool {
+	path = strings.TrimPrefix(path, "/")
+	if g.re.MatchString(path) {
+		return true
+	}
+
+	for i := strings.LastIndex(path, "/"); i > 0; i = strings.LastIndex(path, "/") {
+		path = path[:i]
+		if g.re.MatchString(path + "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func globToRegexp(pattern string) string {
+	p := strings.TrimSpace(pattern)
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(p[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := p[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(p):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if dirOnly {
+		// Directory pattern: only matches paths inside that directory
+		b.WriteString("/.*")
+	} else {
+		// "dir/" is what MatchOrParent feeds in for parent directories
+		b.WriteString("/?")
+	}
+	b.WriteString("$")
+
+	return b.String()
+}